@@ -0,0 +1,237 @@
+package structscan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// AdaptTo returns a copy of the schema in which every plain Scan().To(path) whose
+// column is reported as text by rows.ColumnTypes() parses the text into the kind of
+// its field, e.g. String().ParseInt(10, 64) for an int64. This suits drivers that
+// return all values as text. Time fields use TimeFlexible, trying first the layout for
+// the database type name of the column: time.DateOnly for DATE, time.TimeOnly for TIME,
+// time.DateTime for DATETIME and TIMESTAMP and time.RFC3339Nano otherwise.
+func (s *Schema[T]) AdaptTo(rows *sql.Rows) (*Schema[T], error) {
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) != len(s.scanners) {
+		return nil, fmt.Errorf("adapt: %d columns for %d scanners", len(columns), len(s.scanners))
+	}
+
+	var (
+		typ      = s.typ
+		scanners = make([]Scanner, len(s.scanners))
+	)
+
+	for i, scanner := range s.scanners {
+		scanners[i] = scanner
+
+		d, ok := scanner.(Destination)
+		if !ok || d.base == nil || !textColumn(columns[i]) {
+			continue
+		}
+
+		_, _, dstType, err := destAccessor(typ, s.cfg.resolve(typ, d.path), s.cfg.depth())
+		if err != nil {
+			return nil, err
+		}
+
+		if adapted, ok := adaptScanner(*d.base, columns[i], derefType(dstType), d.path); ok {
+			adapted.expect = d.expect
+			scanners[i] = adapted
+		}
+	}
+
+	return newTypedSchema[T](s.cfg, s.typ, scanners)
+}
+
+var (
+	rawBytesType = reflect.TypeFor[sql.RawBytes]()
+	nullStrType  = reflect.TypeFor[sql.NullString]()
+)
+
+func textColumn(col *sql.ColumnType) bool {
+	switch col.ScanType() {
+	case stringType, bytesType, rawBytesType, nullStrType:
+		return true
+	}
+
+	return false
+}
+
+func adaptScanner(base DefaultScanner, col *sql.ColumnType, dstType reflect.Type, path string) (Destination, bool) {
+	// The field scans itself, e.g. a status parsed from its name.
+	if reflect.PointerTo(dstType).Implements(sqlScannerType) {
+		return Destination{}, false
+	}
+
+	if dstType == timeType {
+		// TimeFlexible, as some drivers parse date and time columns reported as text
+		layouts := append([]string{timeLayout(col.DatabaseTypeName())}, defaultTimeLayouts...)
+
+		return base.TimeFlexible(layouts...).To(path), true
+	}
+
+	switch dstType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return base.String().ParseInt(10, dstType.Bits()).To(path), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return base.String().ParseUint(10, dstType.Bits()).To(path), true
+	case reflect.Float32, reflect.Float64:
+		return base.String().ParseFloat(dstType.Bits()).To(path), true
+	case reflect.Bool:
+		return base.String().ParseBool().To(path), true
+	}
+
+	return Destination{}, false
+}
+
+func timeLayout(databaseType string) string {
+	switch strings.ToUpper(databaseType) {
+	case "DATE":
+		return time.DateOnly
+	case "TIME":
+		return time.TimeOnly
+	case "DATETIME", "TIMESTAMP":
+		return time.DateTime
+	}
+
+	return time.RFC3339Nano
+}
+
+// Querier runs queries, such as *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Check runs query without reading its rows and reports mismatches between its columns
+// and the scanners of the schema, meant for validating queries at startup. Besides the
+// number of columns, it checks that the columns of plain Scan().To(path) scanners can
+// be scanned into their fields, as far as the driver reports their types.
+//
+// The query is executed as given, so statements with side effects, such as INSERT or
+// UPDATE with RETURNING, take effect; pass them in a transaction that is rolled back.
+// Adding LIMIT 0 avoids the cost of a SELECT, though drivers such as SQLite only
+// report column types for returned rows, limiting the check to the number of columns.
+func (s *Schema[T]) Check(ctx context.Context, db Querier, query string, args ...any) (err error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer func() { err = errors.Join(err, rows.Close()) }()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	if len(columns) != len(s.scanners) {
+		return fmt.Errorf("check: %d columns for %d scanners", len(columns), len(s.scanners))
+	}
+
+	var (
+		typ  = s.typ
+		errs []error
+	)
+
+	for i, scanner := range s.scanners {
+		d, ok := scanner.(Destination)
+		if !ok {
+			continue
+		}
+
+		if d.expect != nil {
+			if err := d.expect.check(columns[i]); err != nil {
+				errs = append(errs, fmt.Errorf("column %d (%s) -> %s: %w", i, columns[i].Name(), d.path, err))
+			}
+		}
+
+		if d.base == nil || columns[i].ScanType() == nil {
+			continue
+		}
+
+		_, _, dstType, err := destAccessor(typ, s.cfg.resolve(typ, d.path), s.cfg.depth())
+		if err != nil {
+			return err
+		}
+
+		if !scannable(columns[i], derefType(dstType)) {
+			errs = append(errs, categoryError{ErrNotAssignable, fmt.Errorf("column %d (%s) -> %s: %s is not scannable into %s",
+				i, columns[i].Name(), d.path, columns[i].ScanType(), dstType)})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// scannable reports whether database/sql can scan values of col into dst, assuming it
+// can if either type is not one of the driver's basic kinds. Drivers may parse date and
+// time columns reported as text, so these are accepted for time.Time.
+func scannable(col *sql.ColumnType, dst reflect.Type) bool {
+	src := col.ScanType()
+
+	if isNullType(src) {
+		src = src.Field(0).Type
+	}
+
+	if src.Kind() == reflect.Interface || reflect.PointerTo(dst).Implements(sqlScannerType) {
+		return true
+	}
+
+	var (
+		srcKind = driverKind(src)
+		dstKind = driverKind(dst)
+	)
+
+	if srcKind == reflect.Invalid || dstKind == reflect.Invalid || srcKind == dstKind {
+		return true
+	}
+
+	switch dstKind {
+	case reflect.String:
+		return true
+	case reflect.Int, reflect.Float64, reflect.Bool:
+		return srcKind == reflect.String || srcKind == reflect.Int
+	}
+
+	// time.Time, which database/sql doesn't parse from text
+	dbType := strings.ToUpper(col.DatabaseTypeName())
+
+	return strings.Contains(dbType, "DATE") || strings.Contains(dbType, "TIME")
+}
+
+// driverKind reduces typ to the kinds of driver values, with reflect.Struct standing
+// for time.Time and reflect.String for []byte, or reflect.Invalid for other types.
+func driverKind(typ reflect.Type) reflect.Kind {
+	if typ == timeType {
+		return reflect.Struct
+	}
+
+	//nolint:exhaustive
+	switch typ.Kind() {
+	case reflect.String:
+		return reflect.String
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return reflect.String
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Int
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	case reflect.Bool:
+		return reflect.Bool
+	}
+
+	return reflect.Invalid
+}
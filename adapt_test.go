@@ -0,0 +1,143 @@
+package structscan_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sqlt/structscan"
+)
+
+type Adapted struct {
+	Int    int32
+	Float  float64
+	Bool   bool
+	Time   *time.Time
+	Str    string
+	Status Status
+}
+
+type Status int
+
+func (s *Status) Scan(src any) error {
+	switch src {
+	case "active":
+		*s = 1
+	case "inactive":
+		*s = 0
+	default:
+		return fmt.Errorf("unknown status %v", src)
+	}
+
+	return nil
+}
+
+func TestAdaptTo(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Adapted](
+		structscan.Scan().To("Int"),
+		structscan.Scan().To("Float"),
+		structscan.Scan().To("Bool"),
+		structscan.Nullable().To("Time"),
+		structscan.Scan().To("Str"),
+		structscan.Scan().To("Status"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE adapted (i TEXT, f TEXT, b TEXT, t TEXT, s TEXT, st TEXT);
+		INSERT INTO adapted VALUES ('42', '2.5', 'true', '2024-01-02T10:00:00Z', 'x', 'active'), ('-1', '0', 'false', NULL, 'y', 'inactive');`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT i, f, b, t, s, st FROM adapted")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	adapted, err := schema.AdaptTo(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := adapted.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	expect := []Adapted{
+		{Int: 42, Float: 2.5, Bool: true, Time: &ts, Str: "x", Status: 1},
+		{Int: -1, Str: "y"},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if _, err = schema.AdaptTo(rows); err == nil {
+		t.Fatal("expected error for closed rows")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Adapted](
+		structscan.Scan().To("Int"),
+		structscan.Scan().To("Float"),
+		structscan.Scan().To("Time"),
+		structscan.Scan().String().ToUpper().To("Str"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("CREATE TABLE checked (t DATETIME); INSERT INTO checked VALUES (?)", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = schema.Check(t.Context(), db, "SELECT '1', 2, t, 'x' FROM checked"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = schema.Check(t.Context(), db, "SELECT 1, 2"); err == nil || !strings.Contains(err.Error(), "2 columns for 4 scanners") {
+		t.Fatalf("expected column count error, got %v", err)
+	}
+
+	err = schema.Check(t.Context(), db, "SELECT 1.5, 2, 3, 'x'")
+	if !errors.Is(err, structscan.ErrNotAssignable) {
+		t.Fatalf("expected ErrNotAssignable, got %v", err)
+	}
+
+	for _, mismatch := range []string{"column 0 (1.5) -> Int", "column 2 (3) -> Time"} {
+		if !strings.Contains(err.Error(), mismatch) {
+			t.Fatalf("expected %q in %v", mismatch, err)
+		}
+	}
+
+	if err = schema.Check(t.Context(), db, "SELECT * FROM missing"); err == nil {
+		t.Fatal("expected query error")
+	}
+}
@@ -0,0 +1,496 @@
+package structscan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Auto returns a schema scanning one column per exported field of T in declaration
+// order. The scan tag of a field declares the chain of its scanner as comma separated
+// steps naming the methods to call, case-insensitively, with arguments after "=":
+//
+//	type User struct {
+//		ID      int64
+//		Active  bool      `scan:"string,trimspace,parsebool"`
+//		Created time.Time `scan:"nullable,string,layout=2006-01-02"`
+//		Tags    []string  `scan:"string,split=;"`
+//		Size    int16     `scan:"string,parseint=16;16"`
+//		Secret  string    `scan:"-"`
+//	}
+//
+// Arguments of methods with several parameters are separated by ";". The steps lower,
+// upper and layout are shorthands for ToLower, ToUpper and ParseTime. Fields without a
+// tag use Scan().To(field), fields tagged "-" are skipped.
+func Auto[T any]() (*Schema[T], error) {
+	typ := derefType(reflect.TypeFor[T]())
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("auto requires a struct type, got %s", typ)
+	}
+
+	var scanners []Scanner
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+
+		tag, tagged := field.Tag.Lookup("scan")
+		if !field.IsExported() || tag == "-" || (field.Anonymous && !tagged) {
+			continue
+		}
+
+		scanner, err := tagScanner(tag, field.Name)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		scanners = append(scanners, scanner)
+	}
+
+	return New[T](scanners...)
+}
+
+// AutoColumns returns a schema scanning columns, e.g. from rows.Columns(), into the
+// exported fields of T, including promoted ones, they name. A column names a field if
+// it equals the db tag of the field or, lacking one, if the matcher set by MatchNames
+// accepts it, by default MatchSnakeCase. Scan tags apply as for Auto.
+func AutoColumns[T any](columns []string, opts ...Option) (*Schema[T], error) {
+	typ := derefType(reflect.TypeFor[T]())
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("auto requires a struct type, got %s", typ)
+	}
+
+	cfg := newConfig(opts)
+
+	match := cfg.match
+	if match == nil {
+		match = MatchSnakeCase
+	}
+
+	scanners := make([]Scanner, len(columns))
+
+	for i, column := range columns {
+		name, _ := strings.CutPrefix(column, cfg.prefix)
+
+		field, path, ok := columnField(typ, name, cfg.prefixes, match)
+		if !ok {
+			return nil, fmt.Errorf("column %s: %w", column, ErrPathNotFound)
+		}
+
+		scanner, err := tagScanner(field.Tag.Get("scan"), path)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", path, err)
+		}
+
+		scanners[i] = scanner
+	}
+
+	return newSchema[T](cfg, scanners)
+}
+
+// columnField returns the field named by column and its path, looking into the struct
+// field named by the longest of prefixes that column starts with, see WithPrefix.
+func columnField(typ reflect.Type, column string, prefixes []string, match NameMatcher) (reflect.StructField, string, bool) {
+	var longest string
+
+	for _, prefix := range prefixes {
+		if len(prefix) > len(longest) && strings.HasPrefix(column, prefix) {
+			longest = prefix
+		}
+	}
+
+	if longest == "" {
+		field, ok := namedField(typ, column, match)
+
+		return field, field.Name, ok
+	}
+
+	parent, ok := namedField(typ, strings.TrimRight(longest, "_."), match)
+	if !ok || derefType(parent.Type).Kind() != reflect.Struct {
+		return reflect.StructField{}, "", false
+	}
+
+	field, ok := namedField(derefType(parent.Type), strings.TrimPrefix(column, longest), match)
+
+	return field, parent.Name + "." + field.Name, ok
+}
+
+func namedField(typ reflect.Type, column string, match NameMatcher) (reflect.StructField, bool) {
+	var matched []reflect.StructField
+
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() || field.Anonymous || field.Tag.Get("scan") == "-" {
+			continue
+		}
+
+		// skip fields hidden by shallower ones of the same name
+		if top, _ := typ.FieldByName(field.Name); !slices.Equal(top.Index, field.Index) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+
+		switch name {
+		case "-":
+			continue
+		case "":
+			if match(column, field.Name) {
+				matched = append(matched, field)
+			}
+		default:
+			if name == column {
+				return field, true
+			}
+		}
+	}
+
+	if len(matched) != 1 {
+		return reflect.StructField{}, false
+	}
+
+	return matched[0], true
+}
+
+// NameMatcher reports whether column names the field, see AutoColumns.
+type NameMatcher func(column, field string) bool
+
+// MatchNames sets the matcher AutoColumns resolves untagged fields with.
+func MatchNames(match NameMatcher) Option {
+	return func(cfg *config) {
+		cfg.match = match
+	}
+}
+
+// WithPrefix makes AutoColumns map columns starting with prefix into the struct field
+// that prefix, without trailing "_" or ".", names, e.g. addr_street to Address.Street
+// for WithPrefix("addr_") and a field Address tagged db:"addr", as used to separate
+// the columns of joined tables.
+func WithPrefix(prefix string) Option {
+	return func(cfg *config) {
+		cfg.prefixes = append(slices.Clip(cfg.prefixes), prefix)
+	}
+}
+
+// ColumnPrefix makes AutoColumns strip prefix from column names before matching them.
+func ColumnPrefix(prefix string) Option {
+	return func(cfg *config) {
+		cfg.prefix = prefix
+	}
+}
+
+// MatchExact matches columns equal to the field name.
+func MatchExact(column, field string) bool {
+	return column == field
+}
+
+// MatchFold matches columns equal to the field name under Unicode case-folding.
+func MatchFold(column, field string) bool {
+	return strings.EqualFold(column, field)
+}
+
+// MatchSnakeCase matches columns equal to the snake case of the field name, e.g.
+// user_id for UserID and http_server for HTTPServer, ignoring case.
+func MatchSnakeCase(column, field string) bool {
+	return strings.EqualFold(column, strings.Join(nameWords(field), "_"))
+}
+
+// MatchLowerCamel matches columns equal to the lower camel case of the field name,
+// e.g. userID for UserID and httpServer for HTTPServer.
+func MatchLowerCamel(column, field string) bool {
+	words := nameWords(field)
+	if len(words) == 0 {
+		return column == ""
+	}
+
+	words[0] = strings.ToLower(words[0])
+
+	return column == strings.Join(words, "")
+}
+
+// nameWords splits a Go identifier at case changes, keeping initialisms together.
+func nameWords(name string) []string {
+	var (
+		runes = []rune(name)
+		words []string
+		start int
+	)
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+
+		switch {
+		case cur == '_':
+			words = append(words, string(runes[start:i]))
+			start = i + 1
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)),
+			unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+
+	return slices.DeleteFunc(words, func(w string) bool { return w == "" })
+}
+
+// MappingConfig declares a schema as data, e.g. decoded from JSON or YAML:
+//
+//	{"columns": [
+//		{"column": "id", "path": "ID"},
+//		{"column": "active", "path": "Active", "transform": ["string", "trimspace", "parsebool"]}
+//	]}
+type MappingConfig struct {
+	Columns []ColumnConfig `json:"columns" yaml:"columns"`
+}
+
+// ColumnConfig maps a column to the field at Path through the Transform steps, which
+// are those of the scan tag DSL described at Auto. Columns are scanned in order, Column
+// only names them in errors reported by FromConfig.
+type ColumnConfig struct {
+	Column    string   `json:"column"              yaml:"column"`
+	Path      string   `json:"path"                yaml:"path"`
+	Transform []string `json:"transform,omitempty" yaml:"transform,omitempty"`
+}
+
+// FromConfig returns a schema built from cfg, so mappings can change without
+// recompiling.
+func FromConfig[T any](cfg MappingConfig) (*Schema[T], error) {
+	scanners := make([]Scanner, len(cfg.Columns))
+
+	for i, col := range cfg.Columns {
+		scanner, err := chainScanner(col.Transform, col.Path)
+		if err != nil {
+			return nil, fmt.Errorf("column %d (%s): %w", i, col.Column, err)
+		}
+
+		scanners[i] = scanner
+	}
+
+	schema, err := New[T](scanners...)
+	if err != nil {
+		return nil, fmt.Errorf("mapping config: %w", err)
+	}
+
+	return schema, nil
+}
+
+var stepAliases = map[string]string{
+	"lower":  "tolower",
+	"upper":  "toupper",
+	"layout": "parsetime",
+}
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]func(args []string) (Scanner, error)
+}{factories: map[string]func(args []string) (Scanner, error){}}
+
+// Register makes a custom scanner available to the scan tag DSL of Auto under name,
+// matched case-insensitively. A registered step must come first in a tag and receives
+// the arguments after "=" separated by ";", e.g. `scan:"money=EUR,tolower"`; the steps
+// after it are methods of the returned scanner. Register panics if name is already
+// registered or factory is nil.
+func Register(name string, factory func(args []string) (Scanner, error)) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if factory == nil {
+		panic("structscan: Register factory is nil")
+	}
+
+	key := strings.ToLower(name)
+
+	if _, dup := registry.factories[key]; dup {
+		panic("structscan: Register called twice for " + name)
+	}
+
+	registry.factories[key] = factory
+}
+
+func registered(name string) (func(args []string) (Scanner, error), bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	factory, ok := registry.factories[strings.ToLower(name)]
+
+	return factory, ok
+}
+
+func tagScanner(tag, path string) (Scanner, error) {
+	if tag == "" {
+		return chainScanner(nil, path)
+	}
+
+	return chainScanner(strings.Split(tag, ","), path)
+}
+
+// chainScanner builds the scanner for path from steps in the DSL described at Auto.
+func chainScanner(steps []string, path string) (Scanner, error) {
+	cur := reflect.ValueOf(Scan())
+
+	for i, step := range steps {
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(step), "=")
+
+		if factory, ok := registered(name); ok {
+			if i > 0 {
+				return nil, fmt.Errorf("step %s: registered steps must come first", step)
+			}
+
+			var args []string
+			if hasArg {
+				args = strings.Split(arg, ";")
+			}
+
+			scanner, err := factory(args)
+			if err != nil {
+				return nil, fmt.Errorf("step %s: %w", step, err)
+			}
+
+			cur = reflect.ValueOf(scanner)
+
+			continue
+		}
+
+		if alias, ok := stepAliases[strings.ToLower(name)]; ok {
+			name = alias
+		}
+
+		next, err := callStep(cur, name, arg, hasArg)
+		if err != nil {
+			return nil, fmt.Errorf("step %s: %w", step, err)
+		}
+
+		cur = next
+	}
+
+	switch s := cur.Interface().(type) {
+	case interface{ To(path string) Destination }:
+		return s.To(path), nil
+	case Scanner:
+		return scanAt(s, path), nil
+	}
+
+	return nil, fmt.Errorf("%s is not a scanner", cur.Type())
+}
+
+// scanAt makes s scan into the field at path instead of the whole destination.
+func scanAt(s Scanner, path string) Destination {
+	return Destination{
+		path: path,
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			indices, key, dstType, err := destAccessor(typ, cfg.resolve(typ, path), cfg.depth())
+			if err != nil {
+				return nil, nil, err
+			}
+
+			src, set, err := s.Scan(dstType)
+			if err != nil {
+				return nil, nil, fmt.Errorf("path %s: %w", path, err)
+			}
+
+			at := compileAccess(typ, indices)
+
+			return src, func(dst reflect.Value) error {
+				return assign(dst, at, key, func(dst reflect.Value, _ struct{}) error {
+					return set(dst)
+				}, struct{}{})
+			}, nil
+		},
+	}
+}
+
+func callStep(cur reflect.Value, name, arg string, hasArg bool) (reflect.Value, error) {
+	for i := range cur.NumMethod() {
+		method := cur.Type().Method(i)
+
+		if !strings.EqualFold(method.Name, name) || method.Name == "To" || method.Name == "ToFunc" || method.Name == "Scan" {
+			continue
+		}
+
+		fn := cur.Method(i)
+
+		if fn.Type().IsVariadic() || fn.Type().NumOut() != 1 {
+			return reflect.Value{}, errors.New("not supported in tags")
+		}
+
+		var args []string
+
+		switch {
+		case fn.Type().NumIn() == 1 && hasArg:
+			args = []string{arg}
+		case hasArg:
+			args = strings.Split(arg, ";")
+		}
+
+		if len(args) != fn.Type().NumIn() {
+			return reflect.Value{}, fmt.Errorf("expected %d arguments, got %d", fn.Type().NumIn(), len(args))
+		}
+
+		in := make([]reflect.Value, len(args))
+
+		for j, a := range args {
+			val, err := stepArg(fn.Type().In(j), a)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			in[j] = val
+		}
+
+		return fn.Call(in)[0], nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("unknown step for %s", cur.Type())
+}
+
+var locationType = reflect.TypeFor[*time.Location]()
+
+func stepArg(typ reflect.Type, arg string) (reflect.Value, error) {
+	val := reflect.New(typ).Elem()
+
+	switch {
+	case typ == locationType:
+		loc, err := LoadLocation(arg)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		val.Set(reflect.ValueOf(loc))
+	case typ.Kind() == reflect.String:
+		val.SetString(arg)
+	case typ.Kind() == reflect.Int32:
+		r, size := utf8.DecodeRuneInString(arg)
+		if size == 0 || size != len(arg) {
+			return reflect.Value{}, fmt.Errorf("invalid rune %q", arg)
+		}
+
+		val.SetInt(int64(r))
+	case typ.Kind() == reflect.Int:
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		val.SetInt(int64(n))
+	case typ.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(arg)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		val.SetBool(b)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported argument type %s", typ)
+	}
+
+	return val, nil
+}
@@ -0,0 +1,290 @@
+package structscan_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sqlt/structscan"
+)
+
+type AutoData struct {
+	ID      int64
+	Active  bool      `scan:"string,trimspace,parsebool"`
+	Created time.Time `scan:"nullable,string,layout=2006-01-02"`
+	Tags    []string  `scan:"string,split=;"`
+	Size    int16     `scan:"string,parseint=16;16"`
+	Name    string    `scan:"string, upper"`
+	Secret  string    `scan:"-"`
+	hidden  string
+}
+
+func TestAuto(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.Auto[AutoData]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, ' true ', '2024-01-02', 'a;b', 'ff', 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := AutoData{
+		ID:      1,
+		Active:  true,
+		Created: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Tags:    []string{"a", "b"},
+		Size:    255,
+		Name:    "X",
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if _, err = structscan.Auto[struct {
+		A string `scan:"string,unknown"`
+	}](); err == nil {
+		t.Fatal("expected unknown step error")
+	}
+
+	if _, err = structscan.Auto[struct {
+		A int16 `scan:"string,parseint=10"`
+	}](); err == nil {
+		t.Fatal("expected argument count error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	t.Cleanup(func() {
+		structscan.Unregister("test_upper")
+		structscan.Unregister("test_constant")
+	})
+
+	structscan.Register("test_upper", func(args []string) (structscan.Scanner, error) {
+		return structscan.String().ToUpper(), nil
+	})
+
+	structscan.Register("test_constant", func(args []string) (structscan.Scanner, error) {
+		if len(args) != 1 {
+			return nil, errors.New("constant requires one argument")
+		}
+
+		return structscan.ScanFunc(func(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+			var src any
+
+			return &src, func(dst reflect.Value) error {
+				dst.SetString(args[0])
+
+				return nil
+			}, nil
+		}), nil
+	})
+
+	type Registered struct {
+		Name     string `scan:"TEST_UPPER,trimspace"`
+		Constant string `scan:"test_constant=fixed"`
+	}
+
+	schema, err := structscan.Auto[Registered]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ' abc ', NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (Registered{Name: "ABC", Constant: "fixed"}); result != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if _, err = structscan.Auto[struct {
+		A string `scan:"string,test_upper"`
+	}](); err == nil {
+		t.Fatal("expected registered step position error")
+	}
+
+	if _, err = structscan.Auto[struct {
+		A string `scan:"test_constant"`
+	}](); err == nil {
+		t.Fatal("expected factory error")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+
+	structscan.Register("Test_Upper", func([]string) (structscan.Scanner, error) { return nil, nil })
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Parallel()
+
+	var cfg structscan.MappingConfig
+
+	err := json.Unmarshal([]byte(`{"columns": [
+		{"column": "id", "path": "ID"},
+		{"column": "active", "path": "Active", "transform": ["string", "trimspace", "parsebool"]},
+		{"column": "tags", "path": "Tags", "transform": ["string", "split=,"]}
+	]}`), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.FromConfig[AutoData](cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 7, ' 1 ', 'a,b'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (AutoData{ID: 7, Active: true, Tags: []string{"a", "b"}}); !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	cfg.Columns = append(cfg.Columns, structscan.ColumnConfig{Column: "bad", Path: "Name", Transform: []string{"unknown"}})
+
+	if _, err = structscan.FromConfig[AutoData](cfg); err == nil || !strings.Contains(err.Error(), "column 3 (bad)") {
+		t.Fatalf("expected column error, got %v", err)
+	}
+}
+
+type Named struct {
+	Base
+	UserID     int64
+	HTTPServer string
+	Created    string   `db:"created_on"`
+	Active     bool     `scan:"string,parsebool"`
+	Address    *Address `db:"addr"`
+	Billing    Address
+}
+
+type Address struct {
+	Street  string
+	ZipCode string `scan:"string,trimspace"`
+}
+
+func TestAutoColumns(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		Query  string
+		Opts   []structscan.Option
+		Expect Named
+	}{
+		"snake case": {
+			Query:  "SELECT 1 AS user_id, 'a' AS http_server, 'b' AS created_on, 'true' AS active, 2 AS id",
+			Expect: Named{Base: Base{ID: 2}, UserID: 1, HTTPServer: "a", Created: "b", Active: true},
+		},
+		"lower camel": {
+			Query:  "SELECT 1 AS userID, 'a' AS httpServer",
+			Opts:   []structscan.Option{structscan.MatchNames(structscan.MatchLowerCamel)},
+			Expect: Named{UserID: 1, HTTPServer: "a"},
+		},
+		"exact with prefix": {
+			Query:  "SELECT 1 AS u_UserID, 'b' AS u_created_on",
+			Opts:   []structscan.Option{structscan.MatchNames(structscan.MatchExact), structscan.ColumnPrefix("u_")},
+			Expect: Named{UserID: 1, Created: "b"},
+		},
+		"prefix": {
+			Query:  "SELECT 1 AS user_id, 'a' AS addr_street, ' 1 ' AS addr_zip_code, 'b' AS \"billing.street\"",
+			Opts:   []structscan.Option{structscan.WithPrefix("addr_"), structscan.WithPrefix("billing.")},
+			Expect: Named{UserID: 1, Address: &Address{Street: "a", ZipCode: "1"}, Billing: Address{Street: "b"}},
+		},
+		"custom": {
+			Query: "SELECT 'a' AS server",
+			Opts: []structscan.Option{structscan.MatchNames(func(column, field string) bool {
+				return strings.HasSuffix(strings.ToLower(field), column)
+			})},
+			Expect: Named{HTTPServer: "a"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rows, err := db.Query(tc.Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer rows.Close()
+
+			columns, err := rows.Columns()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			schema, err := structscan.AutoColumns[Named](columns, tc.Opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := schema.One(rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(result, tc.Expect) {
+				t.Fatalf("not equal: \n expected: %v \n   result: %v", tc.Expect, result)
+			}
+		})
+	}
+
+	if _, err = structscan.AutoColumns[Named]([]string{"unknown"}); !errors.Is(err, structscan.ErrPathNotFound) {
+		t.Fatalf("expected ErrPathNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Package bench provides synthetic rows and benchmark harnesses to measure structscan
+// schemas against plain database/sql scanning or other libraries such as sqlx.
+//
+// Open returns a *sql.DB backed by an in-memory driver that serves pre-generated rows
+// of configurable width, column kinds and NULL ratio for every query, so benchmarks
+// measure scanning rather than a database.
+//
+//	cfg := bench.Config{Rows: 1000, Columns: []bench.Kind{bench.Int, bench.String}, NullRatio: 0.1}
+//	db := bench.Open(cfg)
+//
+//	func BenchmarkSchema(b *testing.B) { bench.All(b, db, schema) }
+//	func BenchmarkBaseline(b *testing.B) { bench.Baseline(b, db, cfg.Dest()...) }
+//	func BenchmarkSQLX(b *testing.B) {
+//		bench.Run(b, db, func(rows *sql.Rows) error {
+//			var dst []Data
+//			return sqlx.StructScan(rows, &dst)
+//		})
+//	}
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-sqlt/structscan"
+)
+
+type Kind int
+
+const (
+	Int Kind = iota
+	Float
+	String
+	Bytes
+	Bool
+	Time
+)
+
+type Config struct {
+	Rows      int
+	Columns   []Kind
+	NullRatio float64
+	Seed      uint64
+}
+
+// Open returns a database serving the rows described by cfg for any query.
+// Column names are c0, c1, ... in the order of cfg.Columns.
+func Open(cfg Config) *sql.DB {
+	var (
+		rnd    = rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+		values = make([][]driver.Value, cfg.Rows)
+		names  = make([]string, len(cfg.Columns))
+	)
+
+	for i := range names {
+		names[i] = "c" + strconv.Itoa(i)
+	}
+
+	for i := range values {
+		values[i] = make([]driver.Value, len(cfg.Columns))
+
+		for j, kind := range cfg.Columns {
+			if cfg.NullRatio > 0 && rnd.Float64() < cfg.NullRatio {
+				continue
+			}
+
+			values[i][j] = generate(rnd, kind)
+		}
+	}
+
+	return sql.OpenDB(connector{cfg: cfg, names: names, values: values})
+}
+
+func generate(rnd *rand.Rand, kind Kind) driver.Value {
+	switch kind {
+	case Int:
+		return rnd.Int64N(1 << 40)
+	case Float:
+		return rnd.Float64() * 1000
+	case String:
+		return "value-" + strconv.FormatInt(rnd.Int64N(1<<20), 36)
+	case Bytes:
+		return []byte("bytes-" + strconv.FormatInt(rnd.Int64N(1<<20), 36))
+	case Bool:
+		return rnd.IntN(2) == 1
+	case Time:
+		return time.Unix(rnd.Int64N(1<<31), 0).UTC()
+	}
+
+	panic(fmt.Sprintf("bench: unknown kind %d", kind))
+}
+
+// Dest returns one nullable destination per column, suitable for Baseline.
+func (c Config) Dest() []any {
+	dest := make([]any, len(c.Columns))
+
+	for i, kind := range c.Columns {
+		switch kind {
+		case Int:
+			dest[i] = new(sql.Null[int64])
+		case Float:
+			dest[i] = new(sql.Null[float64])
+		case String:
+			dest[i] = new(sql.Null[string])
+		case Bytes:
+			dest[i] = new(sql.Null[[]byte])
+		case Bool:
+			dest[i] = new(sql.Null[bool])
+		case Time:
+			dest[i] = new(sql.Null[time.Time])
+		}
+	}
+
+	return dest
+}
+
+// Run benchmarks scan, which receives fresh rows on every iteration.
+func Run(b *testing.B, db *sql.DB, scan func(rows *sql.Rows) error) {
+	b.Helper()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		rows, err := db.Query("")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if err = scan(rows); err != nil {
+			b.Fatal(err)
+		}
+
+		if err = rows.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// All benchmarks schema.All.
+func All[T any](b *testing.B, db *sql.DB, schema *structscan.Schema[T]) {
+	b.Helper()
+
+	Run(b, db, func(rows *sql.Rows) error {
+		_, err := schema.All(rows)
+
+		return err
+	})
+}
+
+// Baseline benchmarks plain rows.Scan into dest, e.g. the result of Config.Dest.
+func Baseline(b *testing.B, db *sql.DB, dest ...any) {
+	b.Helper()
+
+	Run(b, db, func(rows *sql.Rows) error {
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+type connector struct {
+	cfg    Config
+	names  []string
+	values [][]driver.Value
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return conn(c), nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return c
+}
+
+func (c connector) Open(string) (driver.Conn, error) {
+	return conn(c), nil
+}
+
+type conn connector
+
+func (c conn) Prepare(string) (driver.Stmt, error) {
+	return stmt(c), nil
+}
+
+func (c conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("bench: transactions are not supported")
+}
+
+func (c conn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &rows{conn: c}, nil
+}
+
+type stmt conn
+
+func (s stmt) Close() error {
+	return nil
+}
+
+func (s stmt) NumInput() int {
+	return -1
+}
+
+func (s stmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("bench: exec is not supported")
+}
+
+func (s stmt) Query([]driver.Value) (driver.Rows, error) {
+	return &rows{conn: conn(s)}, nil
+}
+
+type rows struct {
+	conn conn
+	next int
+}
+
+func (r *rows) Columns() []string {
+	return r.conn.names
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.conn.values) {
+		return io.EOF
+	}
+
+	copy(dest, r.conn.values[r.next])
+
+	r.next++
+
+	return nil
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	switch r.conn.cfg.Columns[index] {
+	case Int:
+		return "INTEGER"
+	case Float:
+		return "REAL"
+	case String:
+		return "TEXT"
+	case Bytes:
+		return "BLOB"
+	case Bool:
+		return "BOOLEAN"
+	case Time:
+		return "TIMESTAMP"
+	}
+
+	return ""
+}
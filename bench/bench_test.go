@@ -0,0 +1,152 @@
+package bench_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/go-sqlt/structscan"
+	"github.com/go-sqlt/structscan/bench"
+)
+
+type Row struct {
+	Int    int64
+	Float  float64
+	String string
+	Bytes  []byte
+	Bool   bool
+	Time   time.Time
+}
+
+var columns = []bench.Kind{bench.Int, bench.Float, bench.String, bench.Bytes, bench.Bool, bench.Time}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	cfg := bench.Config{Rows: 1000, Columns: columns, NullRatio: 0.5, Seed: 1}
+
+	rows, err := bench.Open(cfg).Query("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != len(columns) || names[0] != "c0" {
+		t.Fatalf("unexpected columns: %v", names)
+	}
+
+	var count, nulls int
+
+	dest := cfg.Dest()
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			t.Fatal(err)
+		}
+
+		//nolint:forcetypeassert
+		if !dest[0].(*sql.Null[int64]).Valid {
+			nulls++
+		}
+
+		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != cfg.Rows {
+		t.Fatalf("expected %d rows, got %d", cfg.Rows, count)
+	}
+
+	if nulls < 400 || nulls > 600 {
+		t.Fatalf("unexpected number of nulls: %d", nulls)
+	}
+}
+
+func BenchmarkSchema(b *testing.B) {
+	cfg := bench.Config{Rows: 1000, Columns: columns}
+
+	schema, err := structscan.New[Row](
+		structscan.Scan().To("Int"),
+		structscan.Scan().To("Float"),
+		structscan.Scan().To("String"),
+		structscan.Scan().To("Bytes"),
+		structscan.Scan().To("Bool"),
+		structscan.Scan().To("Time"),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	bench.All(b, bench.Open(cfg), schema)
+}
+
+func BenchmarkSchemaFast(b *testing.B) {
+	cfg := bench.Config{Rows: 1000, Columns: columns}
+
+	schema, err := structscan.New[Row](
+		structscan.Scan().To("Int"),
+		structscan.Scan().To("Float"),
+		structscan.Scan().To("String"),
+		structscan.Scan().To("Bytes"),
+		structscan.Scan().To("Bool"),
+		structscan.Scan().To("Time"),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	bench.Run(b, bench.Open(cfg), func(rows *sql.Rows) error {
+		_, err := schema.FastAll(rows)
+
+		return err
+	})
+}
+
+func BenchmarkSchemaNullable(b *testing.B) {
+	cfg := bench.Config{Rows: 1000, Columns: columns, NullRatio: 0.2}
+
+	schema, err := structscan.New[Row](
+		structscan.Scan().Nullable().Int().To("Int"),
+		structscan.Scan().Nullable().Float().To("Float"),
+		structscan.Scan().Nullable().String().To("String"),
+		structscan.Scan().Nullable().Bytes().To("Bytes"),
+		structscan.Scan().Nullable().Bool().To("Bool"),
+		structscan.Scan().Nullable().Time().To("Time"),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	bench.All(b, bench.Open(cfg), schema)
+}
+
+func BenchmarkBaseline(b *testing.B) {
+	cfg := bench.Config{Rows: 1000, Columns: columns}
+
+	bench.Baseline(b, bench.Open(cfg), cfg.Dest()...)
+}
+
+func BenchmarkNew(b *testing.B) {
+	for b.Loop() {
+		_, err := structscan.New[Row](
+			structscan.Scan().To("Int"),
+			structscan.Scan().To("Float"),
+			structscan.Scan().To("String"),
+			structscan.Scan().To("Bytes"),
+			structscan.Scan().To("Bool"),
+			structscan.Scan().To("Time"),
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package structscan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Binding selects the field at a path as a query argument, see Bind.
+type Binding struct {
+	path    string
+	version bool
+}
+
+// Bind selects the field at path, using the same syntax as To, e.g. "Address.City".
+func Bind(path string) Binding {
+	return Binding{path: path}
+}
+
+// Version marks the field as the version column for optimistic locking, which must be
+// an integer. Update matches its value in the WHERE clause and increments it, so an
+// update affecting no rows means the row changed since it was read. Timestamps such as
+// updated_at aren't supported, as databases store them with a precision that makes
+// matching the value read unreliable. A Binder has at most one version.
+func (b Binding) Version() Binding {
+	b.version = true
+
+	return b
+}
+
+// Binder extracts query arguments from values of T, the counterpart of a Schema for
+// INSERT and UPDATE statements.
+type Binder[T any] struct {
+	paths []string
+	args  []func(src reflect.Value) any
+	// version is the index of the version binding, or -1.
+	version int
+}
+
+func NewBinder[T any](bindings ...Binding) (*Binder[T], error) {
+	var (
+		typ    = reflect.TypeFor[T]()
+		binder = &Binder[T]{
+			paths:   make([]string, len(bindings)),
+			args:    make([]func(src reflect.Value) any, len(bindings)),
+			version: -1,
+		}
+	)
+
+	for i, b := range bindings {
+		arg, err := bindFunc(typ, b.path)
+		if err != nil {
+			return nil, fmt.Errorf("bind %s: %w", b.path, err)
+		}
+
+		if b.version {
+			if binder.version >= 0 {
+				return nil, fmt.Errorf("bind %s: %s is already the version", b.path, binder.paths[binder.version])
+			}
+
+			if err = versionType(typ, b.path); err != nil {
+				return nil, fmt.Errorf("bind %s: %w", b.path, err)
+			}
+
+			binder.version = i
+		}
+
+		binder.paths[i] = b.path
+		binder.args[i] = arg
+	}
+
+	return binder, nil
+}
+
+// Args returns the values of the bound fields of t in order. Fields behind nil
+// pointers and missing map keys yield nil, i.e. NULL.
+func (b *Binder[T]) Args(t T) []any {
+	return bindArgs(b.args, t)
+}
+
+// Statement is a rendered SQL statement, see Binder.Insert and Binder.Update.
+type Statement[T any] struct {
+	SQL  string
+	args []func(src reflect.Value) any
+}
+
+// Args returns the arguments of the statement's placeholders for t.
+func (s *Statement[T]) Args(t T) []any {
+	return bindArgs(s.args, t)
+}
+
+// Insert renders an INSERT of the bound fields into table, naming the columns by the
+// db tags of the fields as Schema.Columns does, e.g.
+//
+//	INSERT INTO "users" ("id", "name") VALUES ($1, $2)
+func (b *Binder[T]) Insert(d Dialect, table string) (*Statement[T], error) {
+	if len(b.paths) == 0 {
+		return nil, errors.New("insert requires at least one binding")
+	}
+
+	var (
+		typ          = reflect.TypeFor[T]()
+		columns      = make([]string, len(b.paths))
+		placeholders = make([]string, len(b.paths))
+	)
+
+	for i, path := range b.paths {
+		name, err := columnName(typ, path)
+		if err != nil {
+			return nil, err
+		}
+
+		columns[i] = d.Quote(name)
+		placeholders[i] = d.Placeholder(i + 1)
+	}
+
+	return &Statement[T]{
+		SQL: fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			quoteTable(d, table), strings.Join(columns, ", "), strings.Join(placeholders, ", ")),
+		args: b.args,
+	}, nil
+}
+
+// Update renders an UPDATE of table setting the bound fields, except those at
+// keyPaths, for the row matching the fields at keyPaths, e.g.
+//
+//	UPDATE "users" SET "name" = $1 WHERE "id" = $2
+//
+// A binding marked as Version is bumped and matched as well, e.g.
+//
+//	UPDATE "users" SET "name" = $1, "version" = "version" + 1 WHERE "id" = $2 AND "version" = $3
+func (b *Binder[T]) Update(d Dialect, table string, keyPaths ...string) (*Statement[T], error) {
+	return b.update(d, table, keyPaths, func(int) bool { return true })
+}
+
+// ErrNoChanges is reported by UpdatePresent if no bound field is present.
+var ErrNoChanges = errors.New("no changes")
+
+// UpdatePresent is like Update but only sets the bound fields present in p, e.g. the
+// Presence of a row scanned by Schema.AllPresence with the fields changed since marked
+// by Presence.Set, so that read-modify-write flows leave the other columns as they
+// are. It fails with ErrNoChanges if no field besides the key paths and the version is
+// present.
+func (b *Binder[T]) UpdatePresent(d Dialect, table string, p Presence, keyPaths ...string) (*Statement[T], error) {
+	if len(keyPaths) == 0 {
+		return nil, errors.New("update requires at least one key path")
+	}
+
+	present := func(i int) bool {
+		return p.Has(b.paths[i])
+	}
+
+	for i, path := range b.paths {
+		if i != b.version && !slices.Contains(keyPaths, path) && present(i) {
+			return b.update(d, table, keyPaths, present)
+		}
+	}
+
+	return nil, ErrNoChanges
+}
+
+// update renders an UPDATE setting the bound fields for which include returns true.
+func (b *Binder[T]) update(d Dialect, table string, keyPaths []string, include func(i int) bool) (*Statement[T], error) {
+	if len(keyPaths) == 0 {
+		return nil, errors.New("update requires at least one key path")
+	}
+
+	var (
+		typ   = reflect.TypeFor[T]()
+		set   []string
+		where []string
+		args  []func(src reflect.Value) any
+	)
+
+	for i, path := range b.paths {
+		if i == b.version || slices.Contains(keyPaths, path) || !include(i) {
+			continue
+		}
+
+		name, err := columnName(typ, path)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, b.args[i])
+		set = append(set, d.Quote(name)+" = "+d.Placeholder(len(args)))
+	}
+
+	if len(set) == 0 {
+		return nil, errors.New("update requires at least one binding besides the key paths")
+	}
+
+	if b.version >= 0 {
+		path := b.paths[b.version]
+
+		if slices.Contains(keyPaths, path) {
+			return nil, fmt.Errorf("update: version %s is a key path", path)
+		}
+
+		name, err := columnName(typ, path)
+		if err != nil {
+			return nil, err
+		}
+
+		set = append(set, d.Quote(name)+" = "+d.Quote(name)+" + 1")
+	}
+
+	for _, path := range keyPaths {
+		name, err := columnName(typ, path)
+		if err != nil {
+			return nil, err
+		}
+
+		arg, err := bindFunc(typ, path)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+		where = append(where, d.Quote(name)+" = "+d.Placeholder(len(args)))
+	}
+
+	if b.version >= 0 {
+		name, err := columnName(typ, b.paths[b.version])
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, b.args[b.version])
+		where = append(where, d.Quote(name)+" = "+d.Placeholder(len(args)))
+	}
+
+	return &Statement[T]{
+		SQL: fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			quoteTable(d, table), strings.Join(set, ", "), strings.Join(where, " AND ")),
+		args: args,
+	}, nil
+}
+
+// quoteTable quotes each part of a qualified table name such as "public.users".
+func quoteTable(d Dialect, table string) string {
+	parts := strings.Split(table, ".")
+
+	for i, part := range parts {
+		parts[i] = d.Quote(part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func bindArgs[T any](fns []func(src reflect.Value) any, t T) []any {
+	var (
+		src  = reflect.ValueOf(&t)
+		args = make([]any, len(fns))
+	)
+
+	for i, arg := range fns {
+		args[i] = arg(src)
+	}
+
+	return args
+}
+
+// versionType fails for version fields at path that aren't integers.
+func versionType(typ reflect.Type, path string) error {
+	_, key, dstType, err := destAccessor(typ, path, -1)
+	if err != nil {
+		return err
+	}
+
+	if key.IsValid() {
+		return fmt.Errorf("version path %s: map keys can't be versions", path)
+	}
+
+	switch dstType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nil
+	}
+
+	return fmt.Errorf("version path %s: %s is not an integer", path, dstType)
+}
+
+func bindFunc(typ reflect.Type, path string) (func(src reflect.Value) any, error) {
+	indices, key, _, err := destAccessor(typ, path, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("path %s: empty", path)
+	}
+
+	parent, last := indices[:len(indices)-1], indices[len(indices)-1]
+
+	return func(src reflect.Value) any {
+		val, ok := lookup(src, parent)
+		if !ok {
+			return nil
+		}
+
+		val = val.Field(last)
+
+		if key.IsValid() {
+			if val, ok = indirect(val); !ok || val.IsNil() {
+				return nil
+			}
+
+			if val = val.MapIndex(key); !val.IsValid() {
+				return nil
+			}
+		}
+
+		return val.Interface()
+	}, nil
+}
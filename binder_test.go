@@ -0,0 +1,294 @@
+package structscan_test
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-sqlt/structscan"
+)
+
+func TestBinder(t *testing.T) {
+	t.Parallel()
+
+	binder, err := structscan.NewBinder[*Data](
+		structscan.Bind("String"),
+		structscan.Bind("Nested.String"),
+		structscan.Bind("StringMap.color"),
+		structscan.Bind("IntMap.missing"),
+		structscan.Bind("StringPointer"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := &Data{String: "a", StringMap: map[string]string{"color": "red"}, StringPointer: ptr("p")}
+
+	args := binder.Args(value)
+	if expect := []any{"a", nil, "red", nil, value.StringPointer}; !reflect.DeepEqual(args, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, args)
+	}
+
+	if args = binder.Args(nil); !reflect.DeepEqual(args, []any{nil, nil, nil, nil, nil}) {
+		t.Fatalf("expected nil args, got %v", args)
+	}
+
+	schema, err := structscan.New[*Data](
+		structscan.Scan().To("String"),
+		structscan.Scan().Nullable().To("Nested.String"),
+		structscan.Scan().To("StringMap.color"),
+		structscan.Scan().Nullable().Int().To("IntMap.missing"),
+		structscan.Scan().Nullable().To("StringPointer"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ?, ?, ?, ?, ?", binder.Args(value)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result, value) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", value, result)
+	}
+
+	if _, err = structscan.NewBinder[Data](structscan.Bind("Unknown")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestBinderStatements(t *testing.T) {
+	t.Parallel()
+
+	binder, err := structscan.NewBinder[Tagged](structscan.Bind("ID"), structscan.Bind("Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, err := binder.Update(structscan.Postgres, "public.users", "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `UPDATE "public"."users" SET "user_name" = $1 WHERE "id" = $2`; update.SQL != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, update.SQL)
+	}
+
+	if args := update.Args(Tagged{ID: 1, Name: "a"}); !reflect.DeepEqual(args, []any{"a", int64(1)}) {
+		t.Fatalf("unexpected args: %v", args)
+	}
+
+	insert, err := binder.Insert(structscan.SQLite, "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `INSERT INTO "users" ("id", "user_name") VALUES (?, ?)`; insert.SQL != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, insert.SQL)
+	}
+
+	if update, err = binder.Update(structscan.SQLite, "users", "ID"); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, user_name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = db.Exec(insert.SQL, insert.Args(Tagged{ID: 1, Name: "before"})...); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = db.Exec(update.SQL, update.Args(Tagged{ID: 1, Name: "after"})...); err != nil {
+		t.Fatal(err)
+	}
+
+	var name string
+	if err = db.QueryRow("SELECT user_name FROM users WHERE id = 1").Scan(&name); err != nil || name != "after" {
+		t.Fatalf("unexpected name %q: %v", name, err)
+	}
+
+	if _, err = binder.Update(structscan.SQLite, "users"); err == nil {
+		t.Fatal("expected missing key error")
+	}
+}
+
+type Account struct {
+	ID      int64  `db:"id"`
+	Name    string `db:"name"`
+	Note    string `db:"note"`
+	Version int64  `db:"version"`
+}
+
+func TestBinderUpdatePresent(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Account](
+		structscan.Scan().To("ID"),
+		structscan.Scan().Nullable().To("Name"),
+		structscan.Scan().Nullable().To("Note"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binder, err := structscan.NewBinder[Account](structscan.Bind("ID"), structscan.Bind("Name"), structscan.Bind("Note"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, NULL, 'n'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, presence, err := schema.AllPresence(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(presence) != 1 || !presence[0].Has("ID") || presence[0].Has("Name") || !presence[0].Has("Note") {
+		t.Fatalf("unexpected presence: %v", presence)
+	}
+
+	update, err := binder.UpdatePresent(structscan.Postgres, "users", presence[0], "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `UPDATE "users" SET "note" = $1 WHERE "id" = $2`; update.SQL != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, update.SQL)
+	}
+
+	// Setting a field to its zero value is a change once marked.
+	presence[0].Set("Name")
+
+	if update, err = binder.UpdatePresent(structscan.Postgres, "users", presence[0], "ID"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `UPDATE "users" SET "name" = $1, "note" = $2 WHERE "id" = $3`; update.SQL != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, update.SQL)
+	}
+
+	if args := update.Args(result[0]); !reflect.DeepEqual(args, []any{"", "n", int64(1)}) {
+		t.Fatalf("unexpected args: %v", args)
+	}
+
+	var only structscan.Presence
+
+	only.Set("ID")
+
+	if _, err = binder.UpdatePresent(structscan.Postgres, "users", only, "ID"); !errors.Is(err, structscan.ErrNoChanges) {
+		t.Fatalf("expected ErrNoChanges, got %v", err)
+	}
+}
+
+func TestBinderVersion(t *testing.T) {
+	t.Parallel()
+
+	binder, err := structscan.NewBinder[Account](
+		structscan.Bind("ID"), structscan.Bind("Name"), structscan.Bind("Version").Version(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, err := binder.Update(structscan.SQLite, "accounts", "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `UPDATE "accounts" SET "name" = ?, "version" = "version" + 1 WHERE "id" = ? AND "version" = ?`; update.SQL != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, update.SQL)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.SetMaxOpenConns(1)
+
+	if _, err = db.Exec("CREATE TABLE accounts (id INTEGER PRIMARY KEY, name TEXT, version INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = db.Exec("INSERT INTO accounts VALUES (1, 'a', 1)"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expect := range []int64{1, 0} {
+		res, err := db.Exec(update.SQL, update.Args(Account{ID: 1, Name: "b", Version: 1})...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if affected, err := res.RowsAffected(); err != nil || affected != expect {
+			t.Fatalf("expected %d affected rows, got %d: %v", expect, affected, err)
+		}
+	}
+
+	var presence structscan.Presence
+
+	presence.Set("Name")
+
+	changed, err := binder.UpdatePresent(structscan.Postgres, "accounts", presence, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `UPDATE "accounts" SET "name" = $1, "version" = "version" + 1 WHERE "id" = $2 AND "version" = $3`; changed.SQL != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, changed.SQL)
+	}
+
+	type Stamped struct {
+		ID        int64     `db:"id"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+
+	if _, err = structscan.NewBinder[Stamped](structscan.Bind("ID"), structscan.Bind("UpdatedAt").Version()); err == nil {
+		t.Fatal("expected error for a time.Time version")
+	}
+
+	if _, err = structscan.NewBinder[Account](structscan.Bind("Name").Version()); err == nil {
+		t.Fatal("expected error for a string version")
+	}
+
+	if _, err = structscan.NewBinder[Account](structscan.Bind("ID").Version(), structscan.Bind("Version").Version()); err == nil {
+		t.Fatal("expected error for two versions")
+	}
+
+	if _, err = binder.Update(structscan.Postgres, "accounts", "Version"); err == nil {
+		t.Fatal("expected error for a version key path")
+	}
+}
@@ -0,0 +1,133 @@
+package structscan
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"reflect"
+)
+
+// DynamicField declares a field of the struct of a DynamicSchema, scanned from one
+// column.
+type DynamicField struct {
+	// Name is the name of the field, which must be exported.
+	Name string
+	// Type is the type of the field.
+	Type reflect.Type
+	// Tag is the struct tag of the field, e.g. `json:"name"` for exports.
+	Tag reflect.StructTag
+	// Scanner converts the column, e.g. Scan().String().TrimSpace(), defaulting to
+	// Scan(). It must have a To method.
+	Scanner Scanner
+}
+
+// DynamicSchema scans rows into structs built at runtime, see NewDynamic.
+type DynamicSchema struct {
+	typ    reflect.Type
+	schema *Schema[any]
+}
+
+// NewDynamic returns a schema scanning rows into structs with the fields, one per
+// column in order, of a type built with reflect.StructOf, e.g. for query tools and
+// report builders whose columns are only known at runtime. The paths of each distinct
+// set of fields stay cached for the life of the process, like those of any type.
+func NewDynamic(fields ...DynamicField) (*DynamicSchema, error) {
+	return NewDynamicWith(nil, fields...)
+}
+
+// NewDynamicWith is NewDynamic with opts applied. Options for identity maps are
+// rejected with an error, as the structs aren't pointers.
+func NewDynamicWith(opts []Option, fields ...DynamicField) (*DynamicSchema, error) {
+	var (
+		structFields = make([]reflect.StructField, len(fields))
+		scanners     = make([]Scanner, len(fields))
+		seen         = make(map[string]bool, len(fields))
+	)
+
+	for i, field := range fields {
+		if !token.IsIdentifier(field.Name) || !token.IsExported(field.Name) {
+			return nil, fmt.Errorf("dynamic: field %q is not an exported identifier", field.Name)
+		}
+
+		if seen[field.Name] {
+			return nil, fmt.Errorf("dynamic: duplicate field %s", field.Name)
+		}
+
+		seen[field.Name] = true
+
+		if field.Type == nil {
+			return nil, fmt.Errorf("dynamic: field %s has no type", field.Name)
+		}
+
+		structFields[i] = reflect.StructField{Name: field.Name, Type: field.Type, Tag: field.Tag}
+
+		scanner := field.Scanner
+		if scanner == nil {
+			scanner = Scan()
+		}
+
+		to, ok := scanner.(interface{ To(path string) Destination })
+		if !ok {
+			return nil, fmt.Errorf("dynamic: field %s: %T has no To method", field.Name, scanner)
+		}
+
+		scanners[i] = to.To(field.Name)
+	}
+
+	typ := reflect.StructOf(structFields)
+
+	schema, err := newTypedSchema[any](newConfig(opts), typ, scanners)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DynamicSchema{typ: typ, schema: schema}, nil
+}
+
+// Type returns the struct type of the schema.
+func (s *DynamicSchema) Type() reflect.Type {
+	return s.typ
+}
+
+// All returns the structs of all rows, as values of Type.
+func (s *DynamicSchema) All(rows Rows) ([]any, error) {
+	return s.AllContext(context.Background(), rows)
+}
+
+// AllContext is like All, passing ctx to the schema's Instrumentation.
+func (s *DynamicSchema) AllContext(ctx context.Context, rows Rows) (result []any, err error) {
+	end := s.schema.cfg.startScan(ctx, "All")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.schema.GetRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.schema.PutRunner(runner)
+
+	interned := make([]map[any]reflect.Value, len(runner.intern))
+
+	for i := range runner.intern {
+		interned[i] = map[any]reflect.Value{}
+	}
+
+	next := func() reflect.Value {
+		return reflect.New(s.typ).Elem()
+	}
+
+	err = runner.each(rows, runner.onError, next, func(_ int, dst reflect.Value) error {
+		for i, in := range runner.intern {
+			in.apply(dst, interned[i])
+		}
+
+		result = append(result, dst.Interface())
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, rows.Err()
+}
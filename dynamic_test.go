@@ -0,0 +1,130 @@
+package structscan_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-sqlt/structscan"
+)
+
+func TestNewDynamic(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = structscan.NewDynamic(structscan.DynamicField{Name: "name", Type: reflect.TypeFor[string]()}); err == nil {
+		t.Fatal("expected error for unexported field")
+	}
+
+	if _, err = structscan.NewDynamic(structscan.DynamicField{
+		Name:    "Name",
+		Type:    reflect.TypeFor[int](),
+		Scanner: structscan.String().ToUpper(),
+	}); !errors.Is(err, structscan.ErrNotAssignable) {
+		t.Fatalf("expected ErrNotAssignable, got %v", err)
+	}
+
+	schema, err := structscan.NewDynamic(
+		structscan.DynamicField{Name: "ID", Type: reflect.TypeFor[int64](), Tag: `json:"id"`},
+		structscan.DynamicField{Name: "Name", Type: reflect.TypeFor[string](), Tag: `json:"name"`, Scanner: structscan.String().ToUpper()},
+		structscan.DynamicField{Name: "Day", Type: reflect.TypeFor[*time.Time](), Tag: `json:"day"`, Scanner: structscan.Nullable().String().ParseTime(time.DateOnly)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'a', '2024-01-02' UNION ALL SELECT 2, 'b', NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range result {
+		if reflect.TypeOf(r) != schema.Type() {
+			t.Fatalf("expected %s, got %T", schema.Type(), r)
+		}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `[{"id":1,"name":"A","day":"2024-01-02T00:00:00Z"},{"id":2,"name":"B","day":null}]`; string(data) != expect {
+		t.Fatalf("not equal: \n expected: %s \n   result: %s", expect, data)
+	}
+}
+
+func TestNewDynamicWith(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var failed []error
+
+	schema, err := structscan.NewDynamicWith(
+		[]structscan.Option{
+			structscan.SkipNullRows(),
+			structscan.WithRowErrorHandler(func(_ int, err error) error {
+				failed = append(failed, err)
+
+				return nil
+			}),
+		},
+		structscan.DynamicField{Name: "ID", Type: reflect.TypeFor[int64](), Scanner: structscan.Nullable().String().ParseInt(10, 64)},
+		structscan.DynamicField{Name: "Name", Type: reflect.TypeFor[string](), Scanner: structscan.Nullable()},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '1', 'a' UNION ALL SELECT NULL, NULL UNION ALL SELECT 'x', 'b'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `[{"ID":1,"Name":"a"}]`; string(data) != expect {
+		t.Fatalf("not equal: \n expected: %s \n   result: %s", expect, data)
+	}
+
+	var fe *structscan.FieldError
+
+	if len(failed) != 1 || !errors.As(failed[0], &fe) || fe.Path != "ID" || fe.Row != 3 {
+		t.Fatalf("expected field error of row 3, got %v", failed)
+	}
+
+	if _, err = structscan.NewDynamicWith(
+		[]structscan.Option{structscan.IdentityMap("ID")},
+		structscan.DynamicField{Name: "ID", Type: reflect.TypeFor[int64]()},
+	); err == nil {
+		t.Fatal("expected error for identity map")
+	}
+}
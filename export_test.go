@@ -0,0 +1,11 @@
+package structscan
+
+import "strings"
+
+// Unregister removes the scanner registered under name, so tests can rerun.
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.factories, strings.ToLower(name))
+}
@@ -0,0 +1,49 @@
+// Package otelstructscan records structscan scans as OpenTelemetry spans.
+//
+//	schema, err := schema.With(structscan.WithInstrumentation(otelstructscan.New(otel.GetTracerProvider())))
+//
+//	users, err := schema.AllContext(ctx, rows)
+package otelstructscan
+
+import (
+	"context"
+
+	"github.com/go-sqlt/structscan"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const name = "github.com/go-sqlt/structscan"
+
+// RowCountKey is the span attribute holding the number of values a scan returned.
+const RowCountKey = attribute.Key("structscan.rows")
+
+type instrumentation struct {
+	tracer trace.Tracer
+}
+
+// New returns an instrumentation starting a span named after the scan method, e.g.
+// "structscan.All", for every scan.
+func New(tp trace.TracerProvider) structscan.Instrumentation {
+	return instrumentation{tracer: tp.Tracer(name)}
+}
+
+func (i instrumentation) OnScanStart(ctx context.Context, op string) context.Context {
+	ctx, _ = i.tracer.Start(ctx, "structscan."+op, trace.WithSpanKind(trace.SpanKindInternal))
+
+	return ctx
+}
+
+func (i instrumentation) OnScanEnd(ctx context.Context, rowCount int, err error) {
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(RowCountKey.Int(rowCount))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,85 @@
+package otelstructscan_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/go-sqlt/structscan"
+	"github.com/go-sqlt/structscan/otelstructscan"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	_ "modernc.org/sqlite"
+)
+
+type Data struct {
+	Int int64
+}
+
+func TestInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		recorder = tracetest.NewSpanRecorder()
+		tp       = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	)
+
+	schema, err := structscan.New[Data](structscan.Scan().To("Int"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema, err = schema.With(structscan.WithInstrumentation(otelstructscan.New(tp))); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := tp.Tracer("test").Start(t.Context(), "query")
+
+	rows, err := db.Query("SELECT 1 UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.AllContext(ctx, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.OneContext(ctx, rows); err == nil {
+		t.Fatal("expected error")
+	}
+
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	all, one := spans[0], spans[1]
+
+	if all.Name() != "structscan.All" || all.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("unexpected span: %s", all.Name())
+	}
+
+	if attrs := all.Attributes(); len(attrs) != 1 || attrs[0].Key != otelstructscan.RowCountKey || attrs[0].Value.AsInt64() != 2 {
+		t.Fatalf("unexpected attributes: %v", attrs)
+	}
+
+	if one.Name() != "structscan.One" || one.Status().Code != codes.Error {
+		t.Fatalf("unexpected span: %s %v", one.Name(), one.Status())
+	}
+}
@@ -0,0 +1,113 @@
+package structscan
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// MapSchema scans rows into maps from column names to values, see NewMap.
+type MapSchema[V any] struct{}
+
+// NewMap returns a schema scanning each row into a map from the column names
+// rows.Columns reports to the values of the columns, e.g. map[string]any or
+// map[string]string, for tools that don't know the shape of the rows. Values are
+// scanned into V as by database/sql, NULL into the zero value of V.
+func NewMap[V any]() *MapSchema[V] {
+	return &MapSchema[V]{}
+}
+
+// All returns the maps of all rows.
+func (s *MapSchema[V]) All(rows Rows) ([]map[string]V, error) {
+	scan, err := mapScanner[V](rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]V
+
+	for row := 1; rows.Next(); row++ {
+		m, err := scan()
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		result = append(result, m)
+	}
+
+	return result, rows.Err()
+}
+
+// One returns the map of the only row, failing with sql.ErrNoRows for none and
+// ErrTooManyRows for more.
+func (s *MapSchema[V]) One(rows Rows) (map[string]V, error) {
+	scan, err := mapScanner[V](rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, sql.ErrNoRows
+	}
+
+	m, err := scan()
+	if err != nil {
+		return nil, fmt.Errorf("row 1: %w", err)
+	}
+
+	if rows.Next() {
+		return nil, ErrTooManyRows
+	}
+
+	return m, rows.Err()
+}
+
+// mapScanner returns a function scanning the current row of rows into a map.
+func mapScanner[V any](rows Rows) (func() (map[string]V, error), error) {
+	named, ok := rows.(interface{ Columns() ([]string, error) })
+	if !ok {
+		return nil, errors.New("map: rows don't report their columns")
+	}
+
+	columns, err := named.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(columns))
+
+	for _, column := range columns {
+		if seen[column] {
+			return nil, fmt.Errorf("map: duplicate column %s", column)
+		}
+
+		seen[column] = true
+	}
+
+	var (
+		values = make([]sql.Null[V], len(columns))
+		src    = make([]any, len(columns))
+	)
+
+	for i := range values {
+		src[i] = &values[i]
+	}
+
+	return func() (map[string]V, error) {
+		if err := rows.Scan(src...); err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]V, len(columns))
+
+		for i, column := range columns {
+			m[column] = values[i].V
+		}
+
+		return m, nil
+	}, nil
+}
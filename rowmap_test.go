@@ -0,0 +1,78 @@
+package structscan_test
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-sqlt/structscan"
+)
+
+func TestNewMap(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1 AS id, 'a' AS name, NULL AS note UNION ALL SELECT 2, x'62', 1.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := structscan.NewMap[any]().All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []map[string]any{
+		{"id": int64(1), "name": "a", "note": nil},
+		{"id": int64(2), "name": []byte("b"), "note": 1.5},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	rows, err = db.Query("SELECT 1 AS id, NULL AS note")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	one, err := structscan.NewMap[string]().One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := map[string]string{"id": "1", "note": ""}; !reflect.DeepEqual(one, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, one)
+	}
+
+	rows, err = db.Query("SELECT 1 AS id UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = structscan.NewMap[string]().One(rows); !errors.Is(err, structscan.ErrTooManyRows) {
+		t.Fatalf("expected ErrTooManyRows, got %v", err)
+	}
+
+	rows, err = db.Query("SELECT 1 AS id, 2 AS id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = structscan.NewMap[any]().All(rows); err == nil {
+		t.Fatal("expected duplicate column error")
+	}
+}
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"bytes"
 	"database/sql"
 	"fmt"
 
@@ -52,18 +53,32 @@ func main() {
 package structscan
 
 import (
+	"bytes"
+	"cmp"
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding"
+	"encoding/csv"
+	"encoding/gob"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"math"
+	"net"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
 type Rows interface {
@@ -72,11 +87,73 @@ type Rows interface {
 	Err() error
 }
 
+// RowsCloser are Rows that must be closed, such as *sql.Rows, see Schema.AllClose.
+type RowsCloser interface {
+	Rows
+	Close() error
+}
+
 func New[T any](scanners ...Scanner) (*Schema[T], error) {
+	return newSchema[T](newConfig(nil), scanners)
+}
+
+// MustNew is like New but panics on error, e.g. for package-level schemas.
+func MustNew[T any](scanners ...Scanner) *Schema[T] {
+	schema, err := New[T](scanners...)
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+// NewWith is New with opts applied, as needed for options that change how the paths
+// of scanners resolve, such as WithLoosePaths.
+func NewWith[T any](opts []Option, scanners ...Scanner) (*Schema[T], error) {
+	return newSchema[T](newConfig(opts), scanners)
+}
+
+var defaults atomic.Pointer[[]Option]
+
+// Defaults sets options that schemas created afterwards by New, NewWith, NewRunner,
+// AutoColumns and FromConfig start from, e.g. TrimStrings or WithLocation, replacing
+// earlier defaults. Options passed to those functions or With apply after them.
+func Defaults(opts ...Option) {
+	opts = slices.Clone(opts)
+
+	defaults.Store(&opts)
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+
+	if d := defaults.Load(); d != nil {
+		opts = append(slices.Clip(*d), opts...)
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+func newSchema[T any](cfg config, scanners []Scanner) (*Schema[T], error) {
+	return newTypedSchema[T](cfg, reflect.TypeFor[T](), scanners)
+}
+
+// newTypedSchema is newSchema for runners setting values of typ, which differs from T
+// for schemas of types built at runtime, see NewDynamic.
+func newTypedSchema[T any](cfg config, typ reflect.Type, scanners []Scanner) (*Schema[T], error) {
+	cfg.debug = new(atomic.Pointer[debugLog])
+
 	schema := &Schema[T]{
+		cfg:      cfg,
+		scanners: scanners,
+		typ:      typ,
 		pool: &sync.Pool{
 			New: func() any {
-				runner, err := NewRunner[T](scanners...)
+				runner, err := newTypedRunner[T](cfg, typ, scanners)
 				if err != nil {
 					return err
 				}
@@ -86,6 +163,20 @@ func New[T any](scanners ...Scanner) (*Schema[T], error) {
 		},
 	}
 
+	if cfg.poolSize > 0 {
+		schema.runners = make(chan *Runner[T], cfg.poolSize)
+
+		for range min(cfg.poolWarm, cfg.poolSize) {
+			runner, err := newTypedRunner[T](cfg, typ, scanners)
+			if err != nil {
+				return nil, err
+			}
+
+			runner.pooled = true
+			schema.runners <- runner
+		}
+	}
+
 	runner, err := schema.GetRunner()
 	if err != nil {
 		return nil, err
@@ -97,1386 +188,7492 @@ func New[T any](scanners ...Scanner) (*Schema[T], error) {
 }
 
 type Schema[T any] struct {
-	pool *sync.Pool
-}
-
-func (s *Schema[T]) GetRunner() (*Runner[T], error) {
-	switch r := s.pool.Get().(type) {
-	case *Runner[T]:
-		return r, nil
-	case error:
-		return nil, r
-	}
-
-	return nil, errors.New("unknown error")
+	cfg      config
+	scanners []Scanner
+	pool     *sync.Pool
+	runners  chan *Runner[T]
+	// typ is the type runners set, T unless built at runtime, see NewDynamic.
+	typ reflect.Type
 }
 
-func (s *Schema[T]) PutRunner(r *Runner[T]) {
-	s.pool.Put(r)
+// Option configures the behavior of a Schema, see Schema.With.
+type Option func(cfg *config)
+
+type config struct {
+	identity    []string
+	intern      []internSpec
+	maxDepth    int
+	skipNull    bool
+	onError     func(row int, err error) error
+	wrapError   func(path string, col int, err error) error
+	debug       *atomic.Pointer[debugLog]
+	instrument  Instrumentation
+	metrics     Metrics
+	factory     func() any
+	prune       *pruner
+	match       NameMatcher
+	prefix      string
+	prefixes    []string
+	loose       bool
+	aliases     map[string]string
+	location    *time.Location
+	nullable    bool
+	trim        bool
+	ignoreExtra bool
+	drain       bool
+	noPool      bool
+	poolSize    int
+	poolWarm    int
 }
 
-func (s *Schema[T]) All(rows Rows) ([]T, error) {
-	runner, err := s.GetRunner()
-	if err != nil {
-		return nil, err
+// depth returns the maximum depth for accessor, -1 for no limit.
+func (c config) depth() int {
+	if c.maxDepth <= 0 {
+		return -1
 	}
 
-	result, err := runner.All(rows)
-
-	s.PutRunner(runner)
-
-	return result, err
+	return c.maxDepth
 }
 
-func (s *Schema[T]) One(rows Rows) (T, error) {
-	runner, err := s.GetRunner()
-	if err != nil {
-		return *new(T), err
+// timeLocation returns the location set by WithLocation, or UTC.
+func (c config) timeLocation() *time.Location {
+	if c.location == nil {
+		return time.UTC
 	}
 
-	result, err := runner.One(rows)
-
-	s.PutRunner(runner)
-
-	return result, err
+	return c.location
 }
 
-func (s *Schema[T]) First(rows Rows) (T, error) {
-	runner, err := s.GetRunner()
-	if err != nil {
-		return *new(T), err
+// nullMode returns mode, or nullSkip for notNull if NullableByDefault is set.
+func (c config) nullMode(mode nullMode) nullMode {
+	if mode == notNull && c.nullable {
+		return nullSkip
 	}
 
-	result, err := runner.First(rows)
-
-	s.PutRunner(runner)
-
-	return result, err
+	return mode
 }
 
-func NewRunner[T any](scanners ...Scanner) (*Runner[T], error) {
-	if len(scanners) == 0 {
-		var (
-			typ = derefType(reflect.TypeFor[T]())
-			val = reflect.New(typ)
-		)
-
-		return &Runner[T]{
-			Src: []any{val.Interface()},
-			Set: []func(dst reflect.Value) error{
-				func(dst reflect.Value) error {
-					dst.Set(val.Elem())
-
-					return nil
-				},
-			},
-		}, nil
+// resolve rewrites path into the field names accessor expects, see WithLoosePaths and
+// PathAlias.
+func (c config) resolve(typ reflect.Type, path string) string {
+	if alias, ok := c.aliases[path]; ok {
+		path = alias
 	}
 
-	var (
-		typ = derefType(reflect.TypeFor[T]())
-		src = make([]any, len(scanners))
-		set = make([]func(dst reflect.Value) error, len(scanners))
-		err error
-	)
-
-	for i, s := range scanners {
-		src[i], set[i], err = s.Scan(typ)
-		if err != nil {
-			return nil, err
-		}
+	if !c.loose || path == "" {
+		return path
 	}
 
-	return &Runner[T]{
-		Src: src,
-		Set: set,
-	}, nil
-}
-
-type Runner[T any] struct {
-	Src []any
-	Set []func(dst reflect.Value) error
-}
-
-func (r *Runner[T]) All(rows Rows) ([]T, error) {
-	var result []T
+	segments := strings.Split(path, ".")
 
-	for rows.Next() {
-		if err := rows.Scan(r.Src...); err != nil {
-			return nil, err
+	for i, segment := range segments {
+		t := derefType(typ)
+		if t.Kind() != reflect.Struct {
+			break
 		}
 
-		var (
-			t   T
-			dst = deref(reflect.ValueOf(&t))
-		)
-
-		for i, set := range r.Set {
-			if set != nil {
-				if err := set(dst); err != nil {
-					return nil, fmt.Errorf("scanner at position %d: %w", i, err)
-				}
+		sf, ok := t.FieldByName(segment)
+		if !ok {
+			sf, ok = t.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, segment) })
+			if !ok {
+				break
 			}
+
+			segments[i] = sf.Name
 		}
 
-		result = append(result, t)
+		typ = sf.Type
 	}
 
-	return result, rows.Err()
+	return strings.Join(segments, ".")
 }
 
-var ErrTooManyRows = errors.New("too many rows")
-
-func (r *Runner[T]) One(rows Rows) (T, error) {
-	var (
-		t   T
-		dst = deref(reflect.ValueOf(&t))
-	)
+// With returns a copy of the schema with the given options applied.
+func (s *Schema[T]) With(opts ...Option) (*Schema[T], error) {
+	cfg := s.cfg
 
-	if !rows.Next() {
-		return t, sql.ErrNoRows
+	for _, opt := range opts {
+		opt(&cfg)
 	}
 
-	if err := rows.Scan(r.Src...); err != nil {
-		return t, err
+	return newTypedSchema[T](cfg, s.typ, s.scanners)
+}
+
+// WithLoosePaths makes the paths of To match field names case-insensitively, e.g.
+// To("createdat") sets CreatedAt. Exact matches take precedence.
+func WithLoosePaths() Option {
+	return func(cfg *config) {
+		cfg.loose = true
 	}
+}
 
-	for _, set := range r.Set {
-		if set != nil {
-			if err := set(dst); err != nil {
-				return t, err
-			}
+// PathAlias makes To(alias) set the field at path, e.g. for names from external
+// metadata.
+func PathAlias(alias, path string) Option {
+	return func(cfg *config) {
+		cfg.aliases = maps.Clone(cfg.aliases)
+		if cfg.aliases == nil {
+			cfg.aliases = map[string]string{}
 		}
-	}
 
-	if rows.Next() {
-		return t, ErrTooManyRows
+		cfg.aliases[alias] = path
 	}
-
-	return t, rows.Err()
 }
 
-func (r *Runner[T]) First(rows Rows) (T, error) {
-	var (
-		t   T
-		dst = deref(reflect.ValueOf(&t))
-	)
-
-	if !rows.Next() {
-		return t, sql.ErrNoRows
+// NullableByDefault makes scanners not marked Nullable treat NULL as Nullable does
+// instead of failing.
+func NullableByDefault() Option {
+	return func(cfg *config) {
+		cfg.nullable = true
 	}
+}
 
-	if err := rows.Scan(r.Src...); err != nil {
-		return t, err
+// TrimStrings trims white space from strings set to destinations, as if each chain
+// ended in TrimSpace.
+func TrimStrings() Option {
+	return func(cfg *config) {
+		cfg.trim = true
 	}
+}
 
-	for _, set := range r.Set {
-		if set != nil {
-			if err := set(dst); err != nil {
-				return t, err
-			}
-		}
+// IgnoreExtraRows makes One return the first row like First instead of failing with
+// ErrTooManyRows when there are more, noting them in the Debug output.
+func IgnoreExtraRows() Option {
+	return func(cfg *config) {
+		cfg.ignoreExtra = true
 	}
-
-	return t, rows.Err()
 }
 
-type Scanner interface {
-	Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error)
+// DrainRows makes First read the rows after the first before returning, so errors
+// occurring later in the result set are reported.
+func DrainRows() Option {
+	return func(cfg *config) {
+		cfg.drain = true
+	}
 }
 
-func Scan() DefaultScanner {
-	return DefaultScanner{nullable: false}
+// WithoutPool makes the schema build a new Runner per call instead of reusing them,
+// trading allocations for memory that is released with the results.
+func WithoutPool() Option {
+	return func(cfg *config) {
+		cfg.noPool = true
+	}
 }
 
-type DefaultScanner struct {
-	nullable bool
+// PoolSize replaces the sync.Pool of runners by one keeping at most size runners, warm
+// of which are built upfront. Runners beyond size are built per call and dropped.
+func PoolSize(size, warm int) Option {
+	return func(cfg *config) {
+		cfg.poolSize = size
+		cfg.poolWarm = warm
+	}
 }
 
-func Nullable() DefaultScanner {
-	return DefaultScanner{nullable: false}.Nullable()
+// WithLocation places times parsed by layouts without a zone, as by ParseTime and
+// TimeFlexible, in loc instead of UTC, so naive timestamp columns are interpreted
+// consistently. Unix times of TimeFlexible are also returned in loc. Steps after the
+// parsing, such as Format or Convert, see the times in loc. See LoadLocation for
+// looking up named zones.
+func WithLocation(loc *time.Location) Option {
+	return func(cfg *config) {
+		cfg.location = loc
+	}
 }
 
-func (s DefaultScanner) Nullable() DefaultScanner {
-	s.nullable = true
+var locations sync.Map
 
-	return s
-}
+// LoadLocation is time.LoadLocation caching the locations it loads.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		//nolint:forcetypeassert
+		return loc.(*time.Location), nil
+	}
 
-func String() StringScanner[string] {
-	return DefaultScanner{nullable: false}.String()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	locations.Store(name, loc)
+
+	return loc, nil
 }
 
-func (s DefaultScanner) String() StringScanner[string] {
-	return StringScanner[string]{
-		nullable: s.nullable,
-		convert:  func(src string) (string, error) { return src, nil },
+// IdentityMap makes All return the same instance for rows with equal values at paths,
+// so that rows repeated by JOINs share one parent. The schema type must be a pointer.
+func IdentityMap(paths ...string) Option {
+	return func(cfg *config) {
+		cfg.identity = paths
 	}
 }
 
-func Int() IntScanner[int64] {
-	return DefaultScanner{nullable: false}.Int()
+type internSpec struct {
+	path string
+	keys []string
 }
 
-func (s DefaultScanner) Int() IntScanner[int64] {
-	return IntScanner[int64]{
-		nullable: s.nullable,
-		convert:  func(src int64) (int64, error) { return src, nil },
+// Intern makes All share one instance of the pointer field at path between rows whose
+// values at keys (relative to the field) are equal, e.g. Intern("Customer", "ID") for
+// many orders referencing the same customer.
+func Intern(path string, keys ...string) Option {
+	return func(cfg *config) {
+		cfg.intern = append(slices.Clip(cfg.intern), internSpec{path: path, keys: keys})
 	}
 }
 
-func Uint() UintScanner[uint64] {
-	return DefaultScanner{nullable: false}.Uint()
-}
-
-func (s DefaultScanner) Uint() UintScanner[uint64] {
-	return UintScanner[uint64]{
-		nullable: s.nullable,
-		convert:  func(src uint64) (uint64, error) { return src, nil },
+// SkipNullRows makes All drop rows in which every scanned column is NULL, as
+// produced by LEFT JOINs without a match, instead of appending zero values.
+func SkipNullRows() Option {
+	return func(cfg *config) {
+		cfg.skipNull = true
 	}
 }
 
-func Float() FloatScanner[float64] {
-	return DefaultScanner{nullable: false}.Float()
+// WithRowErrorHandler makes All pass rows that fail to scan or convert to fn, along
+// with their row number starting at 1. Returning nil skips the row, returning an error
+// aborts All with it.
+func WithRowErrorHandler(fn func(rowIndex int, err error) error) Option {
+	return func(cfg *config) {
+		cfg.onError = fn
+	}
 }
 
-func (s DefaultScanner) Float() FloatScanner[float64] {
-	return FloatScanner[float64]{
-		nullable: s.nullable,
-		convert:  func(src float64) (float64, error) { return src, nil },
+// WithErrorWrapper makes scans return the errors fn returns for columns failing to
+// convert or set, with the destination path, if any, and the column index, e.g. to
+// map them to the error codes of an API. err is a *FieldError, which fn should wrap
+// so that errors.Is and errors.As, and with them Metrics, keep working. If fn returns
+// nil, the *FieldError is returned as it is.
+func WithErrorWrapper(fn func(path string, col int, err error) error) Option {
+	return func(cfg *config) {
+		cfg.wrapError = fn
 	}
 }
 
-func Bool() BoolScanner[bool] {
-	return DefaultScanner{nullable: false}.Bool()
+// Instrumentation observes scans, e.g. to record their duration and row count in
+// traces. See the otelstructscan module for an OpenTelemetry implementation.
+type Instrumentation interface {
+	// OnScanStart is called before scanning with the name of the method, e.g. "All",
+	// and returns the context passed to OnScanEnd.
+	OnScanStart(ctx context.Context, op string) context.Context
+	// OnScanEnd is called after scanning with the number of values returned.
+	OnScanEnd(ctx context.Context, rowCount int, err error)
 }
 
-func (s DefaultScanner) Bool() BoolScanner[bool] {
-	return BoolScanner[bool]{
-		nullable: s.nullable,
-		convert:  func(src bool) (bool, error) { return src, nil },
+// WithInstrumentation reports every scan of the schema to inst. Use the Context
+// variants of the scan methods, e.g. AllContext, to relate scans to the caller's trace.
+func WithInstrumentation(inst Instrumentation) Option {
+	return func(cfg *config) {
+		cfg.instrument = inst
 	}
 }
 
-func Time() TimeScanner[time.Time] {
-	return DefaultScanner{nullable: false}.Time()
-}
+// startScan reports the start of the scan op to the Instrumentation, if any, and
+// returns the func reporting its end.
+func (c config) startScan(ctx context.Context, op string) func(rowCount int, err error) {
+	inst := c.instrument
+	if inst == nil {
+		return func(int, error) {}
+	}
 
-func (s DefaultScanner) Time() TimeScanner[time.Time] {
-	return TimeScanner[time.Time]{
-		nullable: s.nullable,
-		convert:  func(src time.Time) (time.Time, error) { return src, nil },
+	ctx = inst.OnScanStart(ctx, op)
+
+	return func(rowCount int, err error) {
+		inst.OnScanEnd(ctx, rowCount, err)
 	}
 }
 
-func Bytes() BytesScanner[[]byte] {
-	return DefaultScanner{nullable: false}.Bytes()
+// Metrics receives counters of a schema, see Counters for an implementation that
+// can be published with expvar or read to feed e.g. Prometheus.
+type Metrics interface {
+	RowScanned()
+	RowFailed()
+	// ConversionError is called for rows failing with ErrConversion, with the path of
+	// the destination, which is empty for scanners without one.
+	ConversionError(path string)
+	// PoolGet is called whenever a runner is taken from the pool, hit reporting
+	// whether it was reused rather than newly created.
+	PoolGet(hit bool)
 }
 
-func (s DefaultScanner) Bytes() BytesScanner[[]byte] {
-	return BytesScanner[[]byte]{
-		nullable: s.nullable,
-		convert:  func(src []byte) ([]byte, error) { return src, nil },
+// WithMetrics reports the counters of the schema to m.
+func WithMetrics(m Metrics) Option {
+	return func(cfg *config) {
+		cfg.metrics = m
 	}
 }
 
-func StringSlice() StringSliceScanner[[]string] {
-	return DefaultScanner{nullable: false}.StringSlice()
+// Counters counts in memory and is safe for concurrent use. It implements
+// expvar.Var, e.g. expvar.Publish("users", &counters).
+type Counters struct {
+	RowsScanned atomic.Int64
+	RowsFailed  atomic.Int64
+	PoolHits    atomic.Int64
+	PoolMisses  atomic.Int64
+
+	mu          sync.Mutex
+	conversions map[string]int64
 }
 
-func (s DefaultScanner) StringSlice() StringSliceScanner[[]string] {
-	return StringSliceScanner[[]string]{
-		nullable: s.nullable,
-		convert:  func(src []string) ([]string, error) { return src, nil },
-	}
+func (c *Counters) RowScanned() {
+	c.RowsScanned.Add(1)
 }
 
-func IntSlice() IntSliceScanner[[]int64] {
-	return DefaultScanner{nullable: false}.IntSlice()
+func (c *Counters) RowFailed() {
+	c.RowsFailed.Add(1)
 }
 
-func (s DefaultScanner) IntSlice() IntSliceScanner[[]int64] {
-	return IntSliceScanner[[]int64]{
-		nullable: s.nullable,
-		convert:  func(src []int64) ([]int64, error) { return src, nil },
+func (c *Counters) ConversionError(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conversions == nil {
+		c.conversions = map[string]int64{}
 	}
-}
 
-func JSON() JSONScanner[[]byte] {
-	return DefaultScanner{nullable: false}.JSON()
+	c.conversions[path]++
 }
 
-func (s DefaultScanner) JSON() JSONScanner[[]byte] {
-	return JSONScanner[[]byte]{
-		nullable: s.nullable,
-		convert:  func(src []byte) ([]byte, error) { return src, nil },
+func (c *Counters) PoolGet(hit bool) {
+	if hit {
+		c.PoolHits.Add(1)
+	} else {
+		c.PoolMisses.Add(1)
 	}
 }
 
-func Text() TextScanner[[]byte] {
-	return DefaultScanner{nullable: false}.Text()
-}
+// ConversionErrors returns the number of conversion errors by destination path.
+func (c *Counters) ConversionErrors() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-func (s DefaultScanner) Text() TextScanner[[]byte] {
-	return TextScanner[[]byte]{
-		nullable: s.nullable,
-		convert:  func(src []byte) ([]byte, error) { return src, nil },
-	}
+	return maps.Clone(c.conversions)
 }
 
-func Binary() BinaryScanner[[]byte] {
-	return DefaultScanner{nullable: false}.Binary()
+// String returns the counters as JSON.
+func (c *Counters) String() string {
+	data, _ := json.Marshal(map[string]any{
+		"rows_scanned":      c.RowsScanned.Load(),
+		"rows_failed":       c.RowsFailed.Load(),
+		"pool_hits":         c.PoolHits.Load(),
+		"pool_misses":       c.PoolMisses.Load(),
+		"conversion_errors": c.ConversionErrors(),
+	})
+
+	return string(data)
 }
 
-func (s DefaultScanner) Binary() BinaryScanner[[]byte] {
-	return BinaryScanner[[]byte]{
-		nullable: s.nullable,
-		convert:  func(src []byte) ([]byte, error) { return src, nil },
+// MaxDepth limits the number of pointers a destination path may allocate while
+// assigning a value, guarding against runaway paths through recursive types.
+// By default, and for n <= 0, there is no limit.
+func MaxDepth(n int) Option {
+	return func(cfg *config) {
+		cfg.maxDepth = n
 	}
 }
 
-func To(path string) Scanner {
-	return DefaultScanner{nullable: false}.To(path)
+// Debug makes the schema log the source value, the resulting destination value and
+// the destination path of every column of every row to w, e.g. to find out why a
+// field stays zero. Debug(nil) turns logging off again; it is safe to toggle while
+// scanning.
+func (s *Schema[T]) Debug(w io.Writer) {
+	if w == nil {
+		s.cfg.debug.Store(nil)
+	} else {
+		s.cfg.debug.Store(&debugLog{w: w})
+	}
 }
 
-func (s DefaultScanner) To(path string) Scanner {
-	return ScanFunc(func(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-		indices, dstType, err := accessor(typ, path)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		if s.nullable {
-			src := reflect.New(reflect.PointerTo(dstType))
+type debugLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
 
-			return src.Interface(), func(dst reflect.Value) error {
-				elem := src.Elem()
+func (d *debugLog) printf(format string, args ...any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-				if elem.IsNil() {
-					return nil
-				}
+	fmt.Fprintf(d.w, format, args...)
+}
 
-				access(dst, indices).Set(elem.Elem())
+func (s *Schema[T]) GetRunner() (*Runner[T], error) {
+	var runner any
+
+	switch {
+	case s.cfg.noPool:
+		runner = s.pool.New()
+	case s.runners != nil:
+		select {
+		case r := <-s.runners:
+			runner = r
+		default:
+			runner = s.pool.New()
+		}
+	default:
+		runner = s.pool.Get()
+	}
 
-				return nil
-			}, nil
+	switch r := runner.(type) {
+	case *Runner[T]:
+		if s.cfg.metrics != nil {
+			s.cfg.metrics.PoolGet(r.pooled)
 		}
 
-		src := reflect.New(dstType)
+		r.pooled = true
 
-		return src.Interface(), func(dst reflect.Value) error {
-			access(dst, indices).Set(src.Elem())
+		return r, nil
+	case error:
+		return nil, r
+	}
 
-			return nil
-		}, nil
-	})
+	return nil, errors.New("unknown error")
 }
 
-func (s DefaultScanner) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
+func (s *Schema[T]) PutRunner(r *Runner[T]) {
+	switch {
+	case s.cfg.noPool:
+	case s.runners != nil:
+		select {
+		case s.runners <- r:
+		default:
+		}
+	default:
+		s.pool.Put(r)
+	}
+}
+
+func (s *Schema[T]) All(rows Rows) ([]T, error) {
+	return s.AllContext(context.Background(), rows)
+}
+
+// AllContext is like All, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllContext(ctx context.Context, rows Rows) (result []T, err error) {
+	end := s.cfg.startScan(ctx, "All")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = runner.All(rows)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+// AllLimit is like All but reads at most limit rows, reporting whether more were left,
+// to guard against unexpectedly unbounded queries.
+func (s *Schema[T]) AllLimit(rows Rows, limit int) ([]T, bool, error) {
+	return s.AllLimitContext(context.Background(), rows, limit)
+}
+
+// AllLimitContext is like AllLimit, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllLimitContext(ctx context.Context, rows Rows, limit int) (result []T, truncated bool, err error) {
+	end := s.cfg.startScan(ctx, "AllLimit")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, false, err
+	}
+
+	result, truncated, err = runner.AllLimit(rows, limit)
+
+	s.PutRunner(runner)
+
+	return result, truncated, err
+}
+
+// AllDistinct is like All but drops rows whose value at keyPath equals that of an
+// earlier row, e.g. parents repeated by the fan-out of a JOIN.
+func (s *Schema[T]) AllDistinct(rows Rows, keyPath string) ([]T, error) {
+	return s.AllDistinctContext(context.Background(), rows, keyPath)
+}
+
+// AllDistinctContext is like AllDistinct, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllDistinctContext(ctx context.Context, rows Rows, keyPath string) (result []T, err error) {
+	end := s.cfg.startScan(ctx, "AllDistinct")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = runner.AllDistinct(rows, keyPath)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+// AllIndexed is like All but also returns the position in the result of the first row
+// with each value at keyPath, for lookups without another pass.
+func (s *Schema[T]) AllIndexed(rows Rows, keyPath string) ([]T, map[any]int, error) {
+	return s.AllIndexedContext(context.Background(), rows, keyPath)
+}
+
+// AllIndexedContext is like AllIndexed, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllIndexedContext(ctx context.Context, rows Rows, keyPath string) (result []T, index map[any]int, err error) {
+	end := s.cfg.startScan(ctx, "AllIndexed")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, index, err = runner.AllIndexed(rows, keyPath)
+
+	s.PutRunner(runner)
+
+	return result, index, err
+}
+
+// AllSorted is like All but returns the rows stably sorted by cmp, e.g. for collations
+// the database lacks.
+func (s *Schema[T]) AllSorted(rows Rows, cmp func(a, b T) int) ([]T, error) {
+	return s.AllSortedContext(context.Background(), rows, cmp)
+}
+
+// AllSortedContext is like AllSorted, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllSortedContext(ctx context.Context, rows Rows, cmp func(a, b T) int) (result []T, err error) {
+	end := s.cfg.startScan(ctx, "AllSorted")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = runner.AllSorted(rows, cmp)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+// AllWhere is like All but drops rows for which keep returns false while scanning, for
+// filters SQL can't express.
+func (s *Schema[T]) AllWhere(rows Rows, keep func(t T) bool) ([]T, error) {
+	return s.AllWhereContext(context.Background(), rows, keep)
+}
+
+// AllWhereContext is like AllWhere, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllWhereContext(ctx context.Context, rows Rows, keep func(t T) bool) (result []T, err error) {
+	end := s.cfg.startScan(ctx, "AllWhere")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = runner.AllWhere(rows, keep)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+// FastAll is like All but decodes every row into the same T, see Runner.FastAll.
+func (s *Schema[T]) FastAll(rows Rows) ([]T, error) {
+	return s.FastAllContext(context.Background(), rows)
+}
+
+// FastAllContext is like FastAll, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) FastAllContext(ctx context.Context, rows Rows) (result []T, err error) {
+	end := s.cfg.startScan(ctx, "FastAll")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = runner.FastAll(rows)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+// AllColumnar is like All but returns the values of the fields of the rows by
+// destination path, see Runner.AllColumnar.
+func (s *Schema[T]) AllColumnar(rows Rows) (Columnar, error) {
+	return s.AllColumnarContext(context.Background(), rows)
+}
+
+// AllColumnarContext is like AllColumnar, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllColumnarContext(ctx context.Context, rows Rows) (result Columnar, err error) {
+	end := s.cfg.startScan(ctx, "AllColumnar")
+	defer func() { end(result.Len, err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return Columnar{}, err
+	}
+
+	result, err = runner.AllColumnar(rows)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+// WriteCSV is like All but writes the rows to w as CSV records, see Runner.WriteCSV.
+func (s *Schema[T]) WriteCSV(rows Rows, w io.Writer, opts CSVOptions) (int, error) {
+	return s.WriteCSVContext(context.Background(), rows, w, opts)
+}
+
+// WriteCSVContext is like WriteCSV, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) WriteCSVContext(ctx context.Context, rows Rows, w io.Writer, opts CSVOptions) (count int, err error) {
+	end := s.cfg.startScan(ctx, "WriteCSV")
+	defer func() { end(count, err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return 0, err
+	}
+
+	count, err = runner.WriteCSV(rows, w, opts)
+
+	s.PutRunner(runner)
+
+	return count, err
+}
+
+// AllPresence is like All but also returns the Presence of each row, see
+// Runner.AllPresence.
+func (s *Schema[T]) AllPresence(rows Rows) ([]T, []Presence, error) {
+	return s.AllPresenceContext(context.Background(), rows)
+}
+
+// AllPresenceContext is like AllPresence, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllPresenceContext(ctx context.Context, rows Rows) (result []T, presence []Presence, err error) {
+	end := s.cfg.startScan(ctx, "AllPresence")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, presence, err = runner.AllPresence(rows)
+
+	s.PutRunner(runner)
+
+	return result, presence, err
+}
+
+// AllLenient is like All but skips rows that fail to scan or convert, returning their
+// errors as RowErrors. The error is only non-nil for failures of no single row, such
+// as those of rows itself.
+func (s *Schema[T]) AllLenient(rows Rows) ([]T, []RowError, error) {
+	return s.AllLenientContext(context.Background(), rows)
+}
+
+// AllLenientContext is like AllLenient, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllLenientContext(ctx context.Context, rows Rows) (result []T, rowErrs []RowError, err error) {
+	end := s.cfg.startScan(ctx, "AllLenient")
+	defer func() { end(len(result), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, rowErrs, err = runner.AllLenient(rows)
+
+	s.PutRunner(runner)
+
+	return result, rowErrs, err
+}
+
+// AllClose is like All but closes rows, joining an error of Close to the result.
+func (s *Schema[T]) AllClose(rows RowsCloser) ([]T, error) {
+	return s.AllCloseContext(context.Background(), rows)
+}
+
+// AllCloseContext is like AllClose, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) AllCloseContext(ctx context.Context, rows RowsCloser) (result []T, err error) {
+	defer func() { err = errors.Join(err, rows.Close()) }()
+
+	return s.AllContext(ctx, rows)
+}
+
+// OneClose is like One but closes rows, joining an error of Close to the result.
+func (s *Schema[T]) OneClose(rows RowsCloser) (T, error) {
+	return s.OneCloseContext(context.Background(), rows)
+}
+
+// OneCloseContext is like OneClose, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) OneCloseContext(ctx context.Context, rows RowsCloser) (result T, err error) {
+	defer func() { err = errors.Join(err, rows.Close()) }()
+
+	return s.OneContext(ctx, rows)
+}
+
+// OneOrZero is like One but reports a missing row by returning false instead of
+// sql.ErrNoRows.
+func (s *Schema[T]) OneOrZero(rows Rows) (T, bool, error) {
+	return s.OneOrZeroContext(context.Background(), rows)
+}
+
+// OneOrZeroContext is like OneOrZero, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) OneOrZeroContext(ctx context.Context, rows Rows) (T, bool, error) {
+	result, err := s.OneContext(ctx, rows)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, false, nil
+	}
+
+	return result, err == nil, err
+}
+
+// MustAll is like All but panics on error.
+func (s *Schema[T]) MustAll(rows Rows) []T {
+	return s.MustAllContext(context.Background(), rows)
+}
+
+// MustAllContext is like MustAll, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) MustAllContext(ctx context.Context, rows Rows) []T {
+	result, err := s.AllContext(ctx, rows)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// MustOne is like One but panics on error.
+func (s *Schema[T]) MustOne(rows Rows) T {
+	return s.MustOneContext(context.Background(), rows)
+}
+
+// MustOneContext is like MustOne, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) MustOneContext(ctx context.Context, rows Rows) T {
+	result, err := s.OneContext(ctx, rows)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+func (s *Schema[T]) One(rows Rows) (T, error) {
+	return s.OneContext(context.Background(), rows)
+}
+
+// OneContext is like One, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) OneContext(ctx context.Context, rows Rows) (result T, err error) {
+	end := s.cfg.startScan(ctx, "One")
+	defer func() { end(rowCount(err), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return *new(T), err
+	}
+
+	result, err = runner.One(rows)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+func (s *Schema[T]) First(rows Rows) (T, error) {
+	return s.FirstContext(context.Background(), rows)
+}
+
+// FirstContext is like First, passing ctx to the schema's Instrumentation.
+func (s *Schema[T]) FirstContext(ctx context.Context, rows Rows) (result T, err error) {
+	end := s.cfg.startScan(ctx, "First")
+	defer func() { end(rowCount(err), err) }()
+
+	runner, err := s.GetRunner()
+	if err != nil {
+		return *new(T), err
+	}
+
+	result, err = runner.First(rows)
+
+	s.PutRunner(runner)
+
+	return result, err
+}
+
+// Exists reports whether rows has a row, without scanning it, e.g. for
+// SELECT 1 FROM ... WHERE ... LIMIT 1.
+func Exists(rows Rows) (bool, error) {
+	if rows.Next() {
+		return true, nil
+	}
+
+	return false, rows.Err()
+}
+
+// Count returns the integer in the single column of the single row of rows, as
+// returned by SELECT count(*).
+func Count(rows Rows) (int64, error) {
+	var n int64
+
+	if !rows.Next() {
+		return 0, sql.ErrNoRows
+	}
+
+	if err := rows.Scan(&n); err != nil {
+		return 0, fmt.Errorf("row 1: %w", err)
+	}
+
+	if rows.Next() {
+		return 0, ErrTooManyRows
+	}
+
+	return n, rows.Err()
+}
+
+func rowCount(err error) int {
+	if err != nil {
+		return 0
+	}
+
+	return 1
+}
+
+// Key returns a function deriving a comparable key from the values at paths, suitable
+// for use as a map key. A single path yields the (dereferenced) field value itself,
+// multiple paths yield a struct value with one field per path. Nil pointers along a
+// path contribute the zero value of the field.
+func (s *Schema[T]) Key(paths ...string) (func(t T) any, error) {
+	return newKey[T](paths)
+}
+
+// Dialect quotes identifiers and renders placeholders for Columns and Binder.
+type Dialect interface {
+	Quote(ident string) string
+	// Placeholder returns the placeholder of the nth argument, starting at 1.
+	Placeholder(n int) string
+}
+
+type dialect struct {
+	open, close string
+	placeholder func(n int) string
+}
+
+func (d dialect) Quote(ident string) string {
+	return d.open + strings.ReplaceAll(ident, d.close, d.close+d.close) + d.close
+}
+
+func (d dialect) Placeholder(n int) string {
+	return d.placeholder(n)
+}
+
+func questionMark(int) string {
+	return "?"
+}
+
+var (
+	// ANSI quotes identifiers with double quotes and uses ? placeholders.
+	ANSI Dialect = dialect{open: `"`, close: `"`, placeholder: questionMark}
+	// SQLite quotes identifiers with double quotes and uses ? placeholders.
+	SQLite Dialect = dialect{open: `"`, close: `"`, placeholder: questionMark}
+	// Postgres quotes identifiers with double quotes and uses $1 placeholders.
+	Postgres Dialect = dialect{open: `"`, close: `"`, placeholder: func(n int) string { return "$" + strconv.Itoa(n) }}
+	// MySQL quotes identifiers with backticks and uses ? placeholders.
+	MySQL Dialect = dialect{open: "`", close: "`", placeholder: questionMark}
+	// SQLServer quotes identifiers with brackets and uses @p1 placeholders.
+	SQLServer Dialect = dialect{open: "[", close: "]", placeholder: func(n int) string { return "@p" + strconv.Itoa(n) }}
+)
+
+// Columns returns the quoted column names of the scanners in order, for use in a
+// SELECT list that matches the schema. The name of a destination is the first part
+// of the db tag of its field, e.g. `db:"created_at"`, or the key of a map path such
+// as "Attrs.color". Scanners without either are reported as errors.
+func (s *Schema[T]) Columns(dialect Dialect) ([]string, error) {
+	var (
+		typ     = derefType(s.typ)
+		columns = make([]string, len(s.scanners))
+	)
+
+	for i, scanner := range s.scanners {
+		d, ok := scanner.(Destination)
+		if !ok || d.path == "" {
+			return nil, fmt.Errorf("column %d: no destination path", i)
+		}
+
+		name, err := columnName(typ, s.cfg.resolve(typ, d.path))
+		if err != nil {
+			return nil, err
+		}
+
+		columns[i] = dialect.Quote(name)
+	}
+
+	return columns, nil
+}
+
+// columnName returns the db tag name of the field at path or the key of a map path.
+func columnName(typ reflect.Type, path string) (string, error) {
+	indices, key, _, err := destAccessor(typ, path, -1)
+	if err != nil {
+		return "", err
+	}
+
+	if key.IsValid() {
+		return key.String(), nil
+	}
+
+	var field reflect.StructField
+
+	for _, idx := range indices {
+		field = derefType(typ).Field(idx)
+		typ = field.Type
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+	if name == "" || name == "-" {
+		return "", fmt.Errorf("path %s: no db tag", path)
+	}
+
+	return name, nil
+}
+
+func newKey[T any](paths []string) (func(t T) any, error) {
+	key, err := keyFunc(reflect.TypeFor[T](), paths)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(t T) any {
+		return key(reflect.ValueOf(&t))
+	}, nil
+}
+
+func keyFunc(typ reflect.Type, paths []string) (func(src reflect.Value) any, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("key requires at least one path")
+	}
+
+	var (
+		indices = make([][]int, len(paths))
+		fields  = make([]reflect.StructField, len(paths))
+	)
+
+	typ = derefType(typ)
+
+	for i, path := range paths {
+		idx, dstType, err := accessor(typ, path, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		if !dstType.Comparable() {
+			return nil, fmt.Errorf("path %s: %s is not comparable", path, dstType)
+		}
+
+		indices[i] = idx
+		fields[i] = reflect.StructField{Name: fmt.Sprintf("K%d", i), Type: dstType}
+	}
+
+	if len(paths) == 1 {
+		zero := reflect.Zero(fields[0].Type).Interface()
+
+		return func(src reflect.Value) any {
+			if val, ok := lookup(src, indices[0]); ok {
+				return val.Interface()
+			}
+
+			return zero
+		}, nil
+	}
+
+	keyType := reflect.StructOf(fields)
+
+	return func(src reflect.Value) any {
+		key := reflect.New(keyType).Elem()
+
+		for i, idx := range indices {
+			if val, ok := lookup(src, idx); ok {
+				key.Field(i).Set(val)
+			}
+		}
+
+		return key.Interface()
+	}, nil
+}
+
+func NewRunner[T any](scanners ...Scanner) (*Runner[T], error) {
+	return newRunner[T](newConfig(nil), scanners)
+}
+
+func newRunner[T any](cfg config, scanners []Scanner) (*Runner[T], error) {
+	return newTypedRunner[T](cfg, reflect.TypeFor[T](), scanners)
+}
+
+// newTypedRunner is newRunner for values of typ, see newTypedSchema.
+func newTypedRunner[T any](cfg config, typ reflect.Type, scanners []Scanner) (*Runner[T], error) {
+	var identity func(t T) any
+
+	if cfg.identity != nil {
+		if typ.Kind() != reflect.Pointer {
+			return nil, fmt.Errorf("identity map requires a pointer type, got %s", typ)
+		}
+
+		key, err := keyFunc(typ, cfg.identity)
+		if err != nil {
+			return nil, fmt.Errorf("identity map: %w", err)
+		}
+
+		identity = func(t T) any {
+			return key(reflect.ValueOf(&t))
+		}
+	}
+
+	interners := make([]interner, len(cfg.intern))
+
+	for i, spec := range cfg.intern {
+		in, err := newInterner(typ, spec)
+		if err != nil {
+			return nil, fmt.Errorf("intern %s: %w", spec.path, err)
+		}
+
+		interners[i] = in
+	}
+
+	if len(scanners) == 0 {
+		val := reflect.New(derefType(typ))
+
+		return &Runner[T]{
+			Src: []any{val.Interface()},
+			Set: []func(dst reflect.Value) error{
+				func(dst reflect.Value) error {
+					dst.Set(val.Elem())
+
+					return nil
+				},
+			},
+			identity:    identity,
+			intern:      interners,
+			skipNull:    cfg.skipNull,
+			ignoreExtra: cfg.ignoreExtra,
+			drain:       cfg.drain,
+			onError:     cfg.onError,
+			wrapError:   cfg.wrapError,
+			debug:       cfg.debug,
+			metrics:     cfg.metrics,
+		}, nil
+	}
+
+	typ = derefType(typ)
+
+	var (
+		src    = make([]any, len(scanners))
+		set    = make([]func(dst reflect.Value) error, len(scanners))
+		paths  = make([]string, len(scanners))
+		err    error
+		dests  = make([][]int, len(scanners))
+		seen   = map[string]int{}
+		expect []*expectation
+	)
+
+	cfg.prune = &pruner{}
+
+	for i, s := range scanners {
+		src[i], set[i], err = scanConfig(s, typ, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if d, ok := s.(Destination); ok && d.expect != nil {
+			if expect == nil {
+				expect = make([]*expectation, len(scanners))
+			}
+
+			expect[i] = d.expect
+		}
+
+		if d, ok := s.(Destination); ok && d.path != "" {
+			paths[i] = cfg.resolve(typ, d.path)
+
+			// Paths like "Name" and "Base.Name" may resolve to the same field.
+			indices, key, _, _ := destAccessor(typ, paths[i], -1)
+
+			dests[i] = indices
+			field := fmt.Sprint(indices, key)
+
+			if j, ok := seen[field]; ok && !(d.merge && scanners[j].(Destination).merge) {
+				return nil, fmt.Errorf("path %s: duplicate destination, already set by %s", d.path, scanners[j].(Destination).path)
+			}
+
+			seen[field] = i
+		}
+	}
+
+	return &Runner[T]{
+		Src:         src,
+		Set:         set,
+		paths:       paths,
+		dests:       dests,
+		prune:       cfg.prune,
+		expect:      expect,
+		identity:    identity,
+		intern:      interners,
+		skipNull:    cfg.skipNull,
+		ignoreExtra: cfg.ignoreExtra,
+		drain:       cfg.drain,
+		onError:     cfg.onError,
+		wrapError:   cfg.wrapError,
+		debug:       cfg.debug,
+		metrics:     cfg.metrics,
+	}, nil
+}
+
+type Runner[T any] struct {
+	Src []any
+	Set []func(dst reflect.Value) error
+
+	paths       []string
+	dests       [][]int
+	prune       *pruner
+	expect      []*expectation
+	onError     func(row int, err error) error
+	wrapError   func(path string, col int, err error) error
+	debug       *atomic.Pointer[debugLog]
+	metrics     Metrics
+	pooled      bool
+	identity    func(t T) any
+	intern      []interner
+	skipNull    bool
+	ignoreExtra bool
+	drain       bool
+}
+
+type interner struct {
+	indices []int
+	key     func(src reflect.Value) any
+}
+
+func newInterner(typ reflect.Type, spec internSpec) (interner, error) {
+	indices, _, err := accessor(typ, spec.path, -1)
+	if err != nil {
+		return interner{}, err
+	}
+
+	if len(indices) == 0 {
+		return interner{}, errors.New("path is required")
+	}
+
+	fieldType := derefType(typ)
+
+	for i, idx := range indices {
+		if i > 0 {
+			fieldType = derefType(fieldType)
+		}
+
+		fieldType = fieldType.Field(idx).Type
+	}
+
+	if fieldType.Kind() != reflect.Pointer {
+		return interner{}, fmt.Errorf("%s is not a pointer", fieldType)
+	}
+
+	key, err := keyFunc(fieldType, spec.keys)
+	if err != nil {
+		return interner{}, err
+	}
+
+	return interner{indices: indices, key: key}, nil
+}
+
+func (in interner) apply(dst reflect.Value, seen map[any]reflect.Value) {
+	parent, ok := lookup(dst, in.indices[:len(in.indices)-1])
+	if !ok {
+		return
+	}
+
+	field := parent.Field(in.indices[len(in.indices)-1])
+
+	if field.IsNil() {
+		return
+	}
+
+	key := in.key(field)
+
+	if prev, ok := seen[key]; ok {
+		field.Set(prev)
+	} else {
+		seen[key] = reflect.ValueOf(field.Interface())
+	}
+}
+
+func (r *Runner[T]) All(rows Rows) ([]T, error) {
+	return r.all(rows, r.onError, nil)
+}
+
+func (r *Runner[T]) AllLimit(rows Rows, limit int) ([]T, bool, error) {
+	if limit < 0 {
+		return nil, false, fmt.Errorf("limit %d is negative", limit)
+	}
+
+	limited := &limitRows{Rows: rows, limit: limit}
+
+	result, err := r.all(limited, r.onError, nil)
+
+	return result, limited.truncated, err
+}
+
+// limitRows ends after limit rows, noting whether rows had more.
+type limitRows struct {
+	Rows
+
+	limit     int
+	read      int
+	truncated bool
+}
+
+func (l *limitRows) Next() bool {
+	if l.read == l.limit {
+		l.truncated = l.truncated || l.Rows.Next()
+
+		return false
+	}
+
+	l.read++
+
+	return l.Rows.Next()
+}
+
+// ColumnTypes returns the column types of rows, if it reports them, for checkColumns.
+func (l *limitRows) ColumnTypes() ([]*sql.ColumnType, error) {
+	if typed, ok := l.Rows.(interface {
+		ColumnTypes() ([]*sql.ColumnType, error)
+	}); ok {
+		return typed.ColumnTypes()
+	}
+
+	return nil, nil
+}
+
+// AllDistinct is like All but drops rows whose value at keyPath equals that of an
+// earlier row, e.g. parents repeated by the fan-out of a JOIN.
+func (r *Runner[T]) AllDistinct(rows Rows, keyPath string) ([]T, error) {
+	key, err := newKey[T]([]string{keyPath})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[any]struct{}{}
+
+	return r.all(rows, r.onError, func(t T) bool {
+		k := key(t)
+
+		if _, ok := seen[k]; ok {
+			return false
+		}
+
+		seen[k] = struct{}{}
+
+		return true
+	})
+}
+
+// AllIndexed is like All but also returns the position in the result of the first row
+// with each value at keyPath.
+func (r *Runner[T]) AllIndexed(rows Rows, keyPath string) ([]T, map[any]int, error) {
+	key, err := newKey[T]([]string{keyPath})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		index = map[any]int{}
+		n     int
+	)
+
+	result, err := r.all(rows, r.onError, func(t T) bool {
+		k := key(t)
+
+		if _, ok := index[k]; !ok {
+			index[k] = n
+		}
+
+		n++
+
+		return true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, index, nil
+}
+
+// AllSorted is like All but returns the rows stably sorted by cmp, e.g. for collations
+// the database lacks.
+func (r *Runner[T]) AllSorted(rows Rows, cmp func(a, b T) int) ([]T, error) {
+	result, err := r.all(rows, r.onError, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(result, cmp)
+
+	return result, nil
+}
+
+// AllWhere is like All but drops rows for which keep returns false while scanning.
+func (r *Runner[T]) AllWhere(rows Rows, keep func(t T) bool) ([]T, error) {
+	return r.all(rows, r.onError, keep)
+}
+
+// FastAll is like All but decodes every row into the same T and appends copies of it,
+// saving an allocation per row for struct types without pointer fields. For pointer
+// types and with IdentityMap, where rows must not share a target, it is All.
+func (r *Runner[T]) FastAll(rows Rows) ([]T, error) {
+	if r.identity != nil || reflect.TypeFor[T]().Kind() == reflect.Pointer {
+		return r.all(rows, r.onError, nil)
+	}
+
+	var (
+		result []T
+		t      T
+	)
+
+	interned := make([]map[any]reflect.Value, len(r.intern))
+
+	for i := range interned {
+		interned[i] = map[any]reflect.Value{}
+	}
+
+	err := r.each(rows, r.onError, reuse(&t), func(_ int, dst reflect.Value) error {
+		for i, in := range r.intern {
+			in.apply(dst, interned[i])
+		}
+
+		result = append(result, t)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, rows.Err()
+}
+
+// Presence is a bitmap of the destination paths of a row, marking those set from a
+// column that wasn't NULL, see Runner.AllPresence. Binder.UpdatePresent writes back
+// only the present fields, so fields changed after scanning should be marked with Set.
+// The zero value has no paths.
+type Presence struct {
+	// paths are shared by the Presence of the rows of a scan.
+	paths []string
+	bits  []uint64
+}
+
+// Has reports whether path is present.
+func (p Presence) Has(path string) bool {
+	i := slices.Index(p.paths, path)
+
+	return i >= 0 && p.bits[i/64]&(1<<(i%64)) != 0
+}
+
+// Set marks path as present, e.g. after changing its field.
+func (p *Presence) Set(path string) {
+	i := slices.Index(p.paths, path)
+	if i < 0 {
+		p.paths = append(slices.Clip(p.paths), path)
+		i = len(p.paths) - 1
+	}
+
+	for len(p.bits) <= i/64 {
+		p.bits = append(p.bits, 0)
+	}
+
+	p.bits[i/64] |= 1 << (i % 64)
+}
+
+// RowError reports a row skipped by AllLenient. Row starts at 1.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	// A FieldError of the row already starts with the row, wrapped ones may not.
+	//nolint:errorlint
+	if fe, ok := e.Err.(*FieldError); ok && fe.Row == e.Row {
+		return fe.Error()
+	}
+
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// Columnar holds the values of the fields of scanned rows as one slice per field, see
+// Runner.AllColumnar.
+type Columnar struct {
+	// Len is the number of rows.
+	Len int
+	// Paths are the destination paths of the scanners, in order.
+	Paths []string
+	// Columns hold a slice of the field type per path, e.g. []int64 for an int64 field,
+	// with the values of all rows.
+	Columns []any
+}
+
+// Column returns the slice of the field at path, or nil if there is none.
+func (c Columnar) Column(path string) any {
+	if i := slices.Index(c.Paths, path); i >= 0 {
+		return c.Columns[i]
+	}
+
+	return nil
+}
+
+// pathField is a field set by a destination path, see Runner.pathFields.
+type pathField struct {
+	path    string
+	indices []int
+	key     reflect.Value
+	typ     reflect.Type
+}
+
+// value returns the field in dst, or the zero value if a parent pointer is nil or the
+// map has no key.
+func (f pathField) value(dst reflect.Value) reflect.Value {
+	if parent, ok := lookup(dst, f.indices[:len(f.indices)-1]); ok {
+		field := parent.Field(f.indices[len(f.indices)-1])
+
+		if !f.key.IsValid() {
+			return field
+		}
+
+		if m, ok := indirect(field); ok && !m.IsNil() {
+			if elem := m.MapIndex(f.key); elem.IsValid() {
+				return elem
+			}
+		}
+	}
+
+	return reflect.Zero(f.typ)
+}
+
+// pathFields returns the fields set by the paths of the scanners, skipping scanners
+// without a path.
+func (r *Runner[T]) pathFields() ([]pathField, error) {
+	var (
+		fields []pathField
+		typ    = reflect.TypeFor[T]()
+	)
+
+	for _, path := range r.paths {
+		if path == "" {
+			continue
+		}
+
+		indices, key, _, err := destAccessor(typ, path, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(indices) == 0 {
+			continue
+		}
+
+		fields = append(fields, pathField{path: path, indices: indices, key: key, typ: fieldType(typ, indices, key)})
+	}
+
+	return fields, nil
+}
+
+// each scans rows, setting each into the value returned by next, and calls fn with the
+// row, starting at 1, and the value if the row scans and converts. Failing rows are
+// passed to onError, which skips the row by returning nil; a nil onError aborts on the
+// first failure. It doesn't report rows.Err.
+func (r *Runner[T]) each(rows Rows, onError func(row int, err error) error, next func() reflect.Value, fn func(row int, dst reflect.Value) error) error {
+	if err := r.checkColumns(rows); err != nil {
+		return err
+	}
+
+	for row := 1; rows.Next(); row++ {
+		if err := rows.Scan(r.Src...); err != nil {
+			r.observe(err)
+
+			if onError == nil {
+				return fmt.Errorf("row %d: %w", row, err)
+			}
+
+			if err = onError(row, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if r.skipNull && allNull(r.Src) {
+			r.observe(nil)
+
+			continue
+		}
+
+		dst := next()
+
+		err := r.set(dst, row)
+
+		r.observe(err)
+
+		if err != nil {
+			if onError == nil {
+				return err
+			}
+
+			if err = onError(row, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = fn(row, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reuse returns a next function for each that resets t to the zero value and returns
+// it as destination, decoding every row into the same T.
+func reuse[T any](t *T) func() reflect.Value {
+	dst := reflect.ValueOf(t).Elem()
+
+	return func() reflect.Value {
+		var zero T
+
+		*t = zero
+
+		return deref(dst)
+	}
+}
+
+// AllColumnar is like All but decodes every row into the same T, as FastAll does, and
+// appends the values of the fields set by paths to one slice per field instead of
+// returning the rows, e.g. for dataframe and Arrow libraries working on columns.
+// Scanners without a path are not included.
+func (r *Runner[T]) AllColumnar(rows Rows) (Columnar, error) {
+	fields, err := r.pathFields()
+	if err != nil {
+		return Columnar{}, err
+	}
+
+	var (
+		result Columnar
+		t      T
+		values = make([]reflect.Value, len(fields))
+	)
+
+	for i, f := range fields {
+		result.Paths = append(result.Paths, f.path)
+		values[i] = reflect.MakeSlice(reflect.SliceOf(f.typ), 0, 0)
+	}
+
+	err = r.each(rows, r.onError, reuse(&t), func(_ int, dst reflect.Value) error {
+		for i, f := range fields {
+			values[i] = reflect.Append(values[i], f.value(dst))
+		}
+
+		result.Len++
+
+		return nil
+	})
+	if err != nil {
+		return Columnar{}, err
+	}
+
+	for _, v := range values {
+		result.Columns = append(result.Columns, v.Interface())
+	}
+
+	return result, rows.Err()
+}
+
+// CSVOptions configure Runner.WriteCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter, defaulting to ','.
+	Comma rune
+	// UseCRLF ends records with \r\n instead of \n.
+	UseCRLF bool
+	// NoHeader omits the header record of paths.
+	NoHeader bool
+	// TimeLayout formats time.Time fields, defaulting to time.RFC3339Nano.
+	TimeLayout string
+}
+
+// WriteCSV is like AllColumnar but writes the rows to w as CSV records, with the
+// paths as header, instead of collecting them, e.g. for export endpoints. Nil pointers
+// are written as empty fields, []byte as text, time.Time by opts.TimeLayout and
+// encoding.TextMarshaler by MarshalText; other values are formatted by fmt.Sprint.
+// On error, the records of the count rows before the failure are still flushed to w.
+func (r *Runner[T]) WriteCSV(rows Rows, w io.Writer, opts CSVOptions) (int, error) {
+	fields, err := r.pathFields()
+	if err != nil {
+		return 0, err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = opts.UseCRLF
+
+	if opts.Comma != 0 {
+		writer.Comma = opts.Comma
+	}
+
+	layout := cmp.Or(opts.TimeLayout, time.RFC3339Nano)
+	record := make([]string, len(fields))
+
+	if !opts.NoHeader {
+		for i, f := range fields {
+			record[i] = f.path
+		}
+
+		if err = writer.Write(record); err != nil {
+			return 0, err
+		}
+	}
+
+	var (
+		count int
+		t     T
+	)
+
+	err = r.each(rows, r.onError, reuse(&t), func(row int, dst reflect.Value) error {
+		for i, f := range fields {
+			val, err := csvField(f.value(dst), layout)
+			if err != nil {
+				return fmt.Errorf("row %d: %s: %w", row, f.path, err)
+			}
+
+			record[i] = val
+		}
+
+		count++
+
+		return writer.Write(record)
+	})
+	if err == nil {
+		err = rows.Err()
+	}
+
+	writer.Flush()
+
+	return count, errors.Join(err, writer.Error())
+}
+
+// csvField formats the value of a field for WriteCSV.
+func csvField(val reflect.Value, layout string) (string, error) {
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return "", nil
+		}
+
+		if val.Kind() == reflect.Pointer {
+			if m, ok := val.Interface().(encoding.TextMarshaler); ok {
+				text, err := m.MarshalText()
+
+				return string(text), err
+			}
+		}
+
+		val = val.Elem()
+	}
+
+	switch v := val.Interface().(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case []byte:
+		return string(v), nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+
+		return string(text), err
+	}
+
+	return fmt.Sprint(val.Interface()), nil
+}
+
+// AllPresence is like All but also returns the Presence of each row, marking the
+// destination paths whose columns weren't NULL. Scanners without Nullable always mark
+// their paths.
+func (r *Runner[T]) AllPresence(rows Rows) ([]T, []Presence, error) {
+	var (
+		paths    []string
+		bits     = make([]int, len(r.paths))
+		presence []Presence
+	)
+
+	for i, path := range r.paths {
+		bits[i] = slices.Index(paths, path)
+
+		if path != "" && bits[i] < 0 {
+			paths = append(paths, path)
+			bits[i] = len(paths) - 1
+		}
+	}
+
+	paths = slices.Clip(paths)
+
+	result, err := r.all(rows, r.onError, func(T) bool {
+		p := Presence{paths: paths, bits: make([]uint64, (len(paths)+63)/64)}
+
+		for i, src := range r.Src {
+			if i < len(bits) && bits[i] >= 0 && !isNull(src) {
+				p.bits[bits[i]/64] |= 1 << (bits[i] % 64)
+			}
+		}
+
+		presence = append(presence, p)
+
+		return true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, presence, nil
+}
+
+// AllLenient is like All but skips rows that fail to scan or convert, returning them
+// as row errors instead. The error reports failures of rows itself.
+func (r *Runner[T]) AllLenient(rows Rows) ([]T, []RowError, error) {
+	var rowErrs []RowError
+
+	result, err := r.all(rows, func(row int, err error) error {
+		rowErrs = append(rowErrs, RowError{Row: row, Err: err})
+
+		return nil
+	}, nil)
+
+	return result, rowErrs, err
+}
+
+// all scans all rows, passing failing rows to onError, which skips the row by
+// returning nil. A nil onError aborts on the first failure. Rows for which a non-nil
+// keep returns false are dropped.
+func (r *Runner[T]) all(rows Rows, onError func(row int, err error) error, keep func(t T) bool) ([]T, error) {
+	var (
+		result []T
+		seen   map[any]T
+		t      *T
+	)
+
+	if r.identity != nil {
+		seen = map[any]T{}
+	}
+
+	interned := make([]map[any]reflect.Value, len(r.intern))
+
+	for i := range interned {
+		interned[i] = map[any]reflect.Value{}
+	}
+
+	next := func() reflect.Value {
+		t = new(T)
+
+		return deref(reflect.ValueOf(t))
+	}
+
+	err := r.each(rows, onError, next, func(_ int, dst reflect.Value) error {
+		if keep != nil && !keep(*t) {
+			return nil
+		}
+
+		for i, in := range r.intern {
+			in.apply(dst, interned[i])
+		}
+
+		if r.identity != nil {
+			key := r.identity(*t)
+
+			if prev, ok := seen[key]; ok {
+				*t = prev
+			} else {
+				seen[key] = *t
+			}
+		}
+
+		result = append(result, *t)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, rows.Err()
+}
+
+// checkColumns validates the expectations of ExpectDBType and ExpectScanType against
+// rows that report their column types.
+func (r *Runner[T]) checkColumns(rows Rows) error {
+	if r.expect == nil {
+		return nil
+	}
+
+	typed, ok := rows.(interface {
+		ColumnTypes() ([]*sql.ColumnType, error)
+	})
+	if !ok {
+		return nil
+	}
+
+	cols, err := typed.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for i, expect := range r.expect {
+		if expect == nil || i >= len(cols) {
+			continue
+		}
+
+		if err := expect.check(cols[i]); err != nil {
+			errs = append(errs, fmt.Errorf("column %d (%s): %w", i, cols[i].Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Runner[T]) observe(err error) {
+	if r.metrics == nil {
+		return
+	}
+
+	r.metrics.RowScanned()
+
+	if err == nil {
+		return
+	}
+
+	r.metrics.RowFailed()
+
+	if fe := (*FieldError)(nil); errors.As(err, &fe) && errors.Is(err, ErrConversion) {
+		r.metrics.ConversionError(fe.Path)
+	}
+}
+
+// set applies the setters to dst, reporting failures as *FieldError.
+func (r *Runner[T]) set(dst reflect.Value, row int) error {
+	var debug *debugLog
+
+	if r.debug != nil {
+		debug = r.debug.Load()
+	}
+
+	var pruned []int
+
+	for i, set := range r.Set {
+		if set == nil {
+			continue
+		}
+
+		err := set(dst)
+
+		if r.prune != nil && r.prune.requested {
+			r.prune.requested = false
+			pruned = append(pruned, i)
+		}
+
+		if debug != nil {
+			r.trace(debug, dst, row, i, err)
+		}
+
+		if err != nil {
+			fe := &FieldError{Column: i, Row: row, Err: err, typ: typeName(dst.Type())}
+
+			if i < len(r.paths) {
+				fe.Path = r.paths[i]
+			}
+
+			if r.wrapError != nil {
+				if err = r.wrapError(fe.Path, i, fe); err != nil {
+					return err
+				}
+			}
+
+			return fe
+		}
+	}
+
+	r.pruneParents(dst, pruned)
+
+	return nil
+}
+
+// pruner notes that a NullablePrune destination was NULL, see Runner.pruneParents.
+type pruner struct {
+	requested bool
+}
+
+// pruneParents sets the nearest pointer parent of the destination of each pruned
+// column to nil, unless a non-NULL column of the row set a field below it, so that the
+// result doesn't depend on the order of the scanners.
+func (r *Runner[T]) pruneParents(dst reflect.Value, pruned []int) {
+	for _, i := range pruned {
+		parent := pruneIndices(nullPrune, dst.Type(), r.dests[i])
+		if len(parent) == 0 {
+			continue
+		}
+
+		set := false
+
+		for j, dest := range r.dests {
+			if j != i && len(dest) >= len(parent) && slices.Equal(dest[:len(parent)], parent) && !isNull(r.Src[j]) {
+				set = true
+
+				break
+			}
+		}
+
+		if !set {
+			pruneParent(dst, parent)
+		}
+	}
+}
+
+func (r *Runner[T]) trace(debug *debugLog, dst reflect.Value, row, column int, err error) {
+	var src any
+
+	if v := reflect.ValueOf(r.Src[column]); v.Kind() == reflect.Pointer && !v.IsNil() {
+		src = v.Elem().Interface()
+	}
+
+	prefix := fmt.Sprintf("row %d, column %d", row, column)
+
+	if column >= len(r.paths) || r.paths[column] == "" {
+		if err != nil {
+			debug.printf("%s: %#v => err: %v\n", prefix, src, err)
+		} else {
+			debug.printf("%s: %#v\n", prefix, src)
+		}
+
+		return
+	}
+
+	prefix += " -> " + dst.Type().Name() + "." + r.paths[column]
+
+	if err != nil {
+		debug.printf("%s: %#v => err: %v\n", prefix, src, err)
+
+		return
+	}
+
+	var val any
+
+	if indices, key, _, err := destAccessor(dst.Type(), r.paths[column], -1); err == nil {
+		if v, ok := lookup(dst, indices); ok && key.IsValid() {
+			v = v.MapIndex(key)
+			if v.IsValid() {
+				val = v.Interface()
+			}
+		} else if ok {
+			val = v.Interface()
+		}
+	}
+
+	debug.printf("%s: %#v => %#v\n", prefix, src, val)
+}
+
+func allNull(src []any) bool {
+	for _, s := range src {
+		if !isNull(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNull reports whether the scan destination src holds NULL.
+func isNull(src any) bool {
+	if valuer, ok := src.(driver.Valuer); ok {
+		v, err := valuer.Value()
+
+		return err == nil && v == nil
+	}
+
+	//nolint:exhaustive
+	switch v := reflect.ValueOf(src).Elem(); v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+var ErrTooManyRows = errors.New("too many rows")
+
+var (
+	// ErrPathNotFound is reported by New for paths that don't resolve to a field.
+	ErrPathNotFound = errors.New("not found")
+	// ErrNotAssignable is reported by New for destinations a scanner can't set.
+	ErrNotAssignable = errors.New("not assignable")
+	// ErrConversion is reported for values a scanner fails to convert.
+	ErrConversion = errors.New("conversion failed")
+	// ErrColumnType is reported for columns not of the types expected by ExpectDBType
+	// or ExpectScanType.
+	ErrColumnType = errors.New("unexpected column type")
+)
+
+// FieldError is returned when setting a destination fails, e.g.
+// "row 132, column 2 -> User.CreatedAt: parsing time ...". Row starts at 1 and
+// Column at 0, Path is empty for scanners without one. The type name is left out for
+// anonymous structs.
+type FieldError struct {
+	Path   string
+	Column int
+	Row    int
+	Err    error
+
+	typ string
+}
+
+func (e *FieldError) Error() string {
+	if e.Path != "" && e.typ != "" {
+		return fmt.Sprintf("row %d, column %d -> %s.%s: %v", e.Row, e.Column, e.typ, e.Path, e.Err)
+	}
+
+	if e.Path != "" {
+		return fmt.Sprintf("row %d, column %d -> %s: %v", e.Row, e.Column, e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("row %d, column %d: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// typeName returns the name of typ without type arguments, e.g. "Pair" for
+// Pair[User, Order], or "" for anonymous types.
+func typeName(typ reflect.Type) string {
+	name, _, _ := strings.Cut(typ.Name(), "[")
+
+	return name
+}
+
+func conversion(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return categoryError{ErrConversion, err}
+}
+
+// categoryError adds one of the sentinel errors to err without changing its message.
+type categoryError struct {
+	category error
+	err      error
+}
+
+func (e categoryError) Error() string {
+	return e.err.Error()
+}
+
+func (e categoryError) Unwrap() []error {
+	return []error{e.category, e.err}
+}
+
+func (r *Runner[T]) One(rows Rows) (T, error) {
+	var (
+		t   T
+		dst = deref(reflect.ValueOf(&t))
+	)
+
+	if err := r.checkColumns(rows); err != nil {
+		return t, err
+	}
+
+	if !rows.Next() {
+		return t, sql.ErrNoRows
+	}
+
+	if err := rows.Scan(r.Src...); err != nil {
+		r.observe(err)
+
+		return t, fmt.Errorf("row 1: %w", err)
+	}
+
+	err := r.set(dst, 1)
+
+	r.observe(err)
+
+	if err != nil {
+		return t, err
+	}
+
+	if rows.Next() {
+		if !r.ignoreExtra {
+			return t, ErrTooManyRows
+		}
+
+		if r.debug != nil {
+			if debug := r.debug.Load(); debug != nil {
+				debug.printf("ignoring rows after row 1\n")
+			}
+		}
+	}
+
+	return t, rows.Err()
+}
+
+func (r *Runner[T]) First(rows Rows) (T, error) {
+	var (
+		t   T
+		dst = deref(reflect.ValueOf(&t))
+	)
+
+	if err := r.checkColumns(rows); err != nil {
+		return t, err
+	}
+
+	if !rows.Next() {
+		return t, sql.ErrNoRows
+	}
+
+	if err := rows.Scan(r.Src...); err != nil {
+		r.observe(err)
+
+		return t, fmt.Errorf("row 1: %w", err)
+	}
+
+	err := r.set(dst, 1)
+
+	r.observe(err)
+
+	if err != nil {
+		return t, err
+	}
+
+	if r.drain {
+		for rows.Next() {
+		}
+	}
+
+	return t, rows.Err()
+}
+
+type Scanner interface {
+	Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error)
+}
+
+func Scan() DefaultScanner {
+	return DefaultScanner{nullable: notNull}
+}
+
+type nullMode uint8
+
+const (
+	notNull nullMode = iota
+	nullSkip
+	nullPrune
+	nullNil
+	nullZero
+)
+
+type DefaultScanner struct {
+	nullable nullMode
+}
+
+func Nullable() DefaultScanner {
+	return DefaultScanner{nullable: notNull}.Nullable()
+}
+
+func (s DefaultScanner) Nullable() DefaultScanner {
+	s.nullable = nullSkip
+
+	return s
+}
+
+// PruneOnNull makes the scanner nullable and, when the column is NULL, resets the
+// nearest pointer along the destination path to nil, so that e.g. a LEFT JOIN without
+// a match leaves Nested nil instead of an empty struct. The pointer is kept if another
+// column of the row that isn't NULL sets a field below it.
+func (s DefaultScanner) PruneOnNull() DefaultScanner {
+	s.nullable = nullPrune
+
+	return s
+}
+
+func NullableNil() DefaultScanner {
+	return DefaultScanner{nullable: notNull}.NullableNil()
+}
+
+// NullableNil makes the scanner nullable and, when the column is NULL, sets the
+// destination, which must be a pointer, slice, map or interface, to nil instead of
+// leaving it as it is, e.g. for structs reused or populated before scanning.
+func (s DefaultScanner) NullableNil() DefaultScanner {
+	s.nullable = nullNil
+
+	return s
+}
+
+func NullableZero() DefaultScanner {
+	return DefaultScanner{nullable: notNull}.NullableZero()
+}
+
+// NullableZero makes the scanner nullable and, when the column is NULL, sets the
+// destination to its zero value, so that values reused across rows never keep the
+// value of a previous row.
+func (s DefaultScanner) NullableZero() DefaultScanner {
+	s.nullable = nullZero
+
+	return s
+}
+
+func String() StringScanner[string] {
+	return DefaultScanner{nullable: notNull}.String()
+}
+
+func (s DefaultScanner) String() StringScanner[string] {
+	return StringScanner[string]{
+		nullable: s.nullable,
+		convert:  func(src string, cfg *config) (string, error) { return src, nil },
+	}
+}
+
+func Int() IntScanner[int64] {
+	return DefaultScanner{nullable: notNull}.Int()
+}
+
+func (s DefaultScanner) Int() IntScanner[int64] {
+	return IntScanner[int64]{
+		nullable: s.nullable,
+		convert:  func(src int64, cfg *config) (int64, error) { return src, nil },
+	}
+}
+
+func Uint() UintScanner[uint64] {
+	return DefaultScanner{nullable: notNull}.Uint()
+}
+
+func (s DefaultScanner) Uint() UintScanner[uint64] {
+	return UintScanner[uint64]{
+		nullable: s.nullable,
+		convert:  func(src uint64, cfg *config) (uint64, error) { return src, nil },
+	}
+}
+
+func Float() FloatScanner[float64] {
+	return DefaultScanner{nullable: notNull}.Float()
+}
+
+func (s DefaultScanner) Float() FloatScanner[float64] {
+	return FloatScanner[float64]{
+		nullable: s.nullable,
+		convert:  func(src float64, cfg *config) (float64, error) { return src, nil },
+	}
+}
+
+func Bool() BoolScanner[bool] {
+	return DefaultScanner{nullable: notNull}.Bool()
+}
+
+func (s DefaultScanner) Bool() BoolScanner[bool] {
+	return BoolScanner[bool]{
+		nullable: s.nullable,
+		convert:  func(src bool, cfg *config) (bool, error) { return src, nil },
+	}
+}
+
+func Time() TimeScanner[time.Time] {
+	return DefaultScanner{nullable: notNull}.Time()
+}
+
+func (s DefaultScanner) Time() TimeScanner[time.Time] {
+	return TimeScanner[time.Time]{
+		nullable: s.nullable,
+		convert:  func(src time.Time, cfg *config) (time.Time, error) { return src, nil },
+	}
+}
+
+func TimeFlexible(layouts ...string) TimeScanner[any] {
+	return DefaultScanner{nullable: notNull}.TimeFlexible(layouts...)
+}
+
+// defaultTimeLayouts are tried by TimeFlexible without layouts, covering the text
+// representations of common drivers.
+var defaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
+// TimeFlexible accepts whatever the driver returns for a timestamp: time.Time as is,
+// strings and []byte parsed with the first matching of layouts (by default common
+// RFC 3339 and SQL formats), and integers and floats as seconds since the Unix epoch.
+func (s DefaultScanner) TimeFlexible(layouts ...string) TimeScanner[any] {
+	if len(layouts) == 0 {
+		layouts = defaultTimeLayouts
+	}
+
+	return TimeScanner[any]{
+		nullable: s.nullable,
+		convert: func(src any, cfg *config) (time.Time, error) {
+			switch v := src.(type) {
+			case time.Time:
+				return v, nil
+			case string:
+				return parseTimeLayouts(v, layouts, cfg.timeLocation())
+			case []byte:
+				return parseTimeLayouts(string(v), layouts, cfg.timeLocation())
+			case int64:
+				return time.Unix(v, 0).In(cfg.timeLocation()), nil
+			case float64:
+				sec, frac := math.Modf(v)
+
+				return time.Unix(int64(sec), int64(frac*1e9)).In(cfg.timeLocation()), nil
+			}
+
+			return time.Time{}, fmt.Errorf("cannot convert %T to time.Time", src)
+		},
+	}
+}
+
+func parseTimeLayouts(v string, layouts []string, loc *time.Location) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := parseTime(layout, v, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("time %q matches none of the layouts %q", v, layouts)
+}
+
+func Bytes() BytesScanner[[]byte] {
+	return DefaultScanner{nullable: notNull}.Bytes()
+}
+
+func (s DefaultScanner) Bytes() BytesScanner[[]byte] {
+	return BytesScanner[[]byte]{
+		nullable: s.nullable,
+		convert:  func(src []byte, cfg *config) ([]byte, error) { return src, nil },
+	}
+}
+
+func StringSlice() StringSliceScanner[[]string] {
+	return DefaultScanner{nullable: notNull}.StringSlice()
+}
+
+func (s DefaultScanner) StringSlice() StringSliceScanner[[]string] {
+	return StringSliceScanner[[]string]{
+		nullable: s.nullable,
+		convert:  func(src []string, cfg *config) ([]string, error) { return src, nil },
+	}
+}
+
+func IntSlice() IntSliceScanner[[]int64] {
+	return DefaultScanner{nullable: notNull}.IntSlice()
+}
+
+func (s DefaultScanner) IntSlice() IntSliceScanner[[]int64] {
+	return IntSliceScanner[[]int64]{
+		nullable: s.nullable,
+		convert:  func(src []int64, cfg *config) ([]int64, error) { return src, nil },
+	}
+}
+
+func UintSlice() UintSliceScanner[[]uint64] {
+	return DefaultScanner{nullable: notNull}.UintSlice()
+}
+
+func (s DefaultScanner) UintSlice() UintSliceScanner[[]uint64] {
+	return UintSliceScanner[[]uint64]{
+		nullable: s.nullable,
+		convert:  func(src []uint64, cfg *config) ([]uint64, error) { return src, nil },
+	}
+}
+
+func JSON() JSONScanner[[]byte] {
+	return DefaultScanner{nullable: notNull}.JSON()
+}
+
+func (s DefaultScanner) JSON() JSONScanner[[]byte] {
+	return JSONScanner[[]byte]{
+		nullable: s.nullable,
+		convert:  func(src []byte, cfg *config) ([]byte, error) { return src, nil },
+	}
+}
+
+func Text() TextScanner[[]byte] {
+	return DefaultScanner{nullable: notNull}.Text()
+}
+
+func (s DefaultScanner) Text() TextScanner[[]byte] {
+	return TextScanner[[]byte]{
+		nullable: s.nullable,
+		convert:  func(src []byte, cfg *config) ([]byte, error) { return src, nil },
+	}
+}
+
+func Binary() BinaryScanner[[]byte] {
+	return DefaultScanner{nullable: notNull}.Binary()
+}
+
+func (s DefaultScanner) Binary() BinaryScanner[[]byte] {
+	return BinaryScanner[[]byte]{
+		nullable: s.nullable,
+		convert:  func(src []byte, cfg *config) ([]byte, error) { return src, nil },
+	}
+}
+
+func Gob() GobScanner[[]byte] {
+	return DefaultScanner{nullable: notNull}.Gob()
+}
+
+func (s DefaultScanner) Gob() GobScanner[[]byte] {
+	return GobScanner[[]byte]{
+		nullable: s.nullable,
+		convert:  func(src []byte, cfg *config) ([]byte, error) { return src, nil },
+	}
+}
+
+func Unmarshal(fn func(data []byte, v any) error) UnmarshalScanner[[]byte] {
+	return DefaultScanner{nullable: notNull}.Unmarshal(fn)
+}
+
+func (s DefaultScanner) Unmarshal(fn func(data []byte, v any) error) UnmarshalScanner[[]byte] {
+	return UnmarshalScanner[[]byte]{
+		nullable:  s.nullable,
+		convert:   func(src []byte, cfg *config) ([]byte, error) { return src, nil },
+		unmarshal: fn,
+	}
+}
+
+func Decimal() DecimalScanner[string] {
+	return DefaultScanner{nullable: notNull}.Decimal()
+}
+
+func (s DefaultScanner) Decimal() DecimalScanner[string] {
+	return DecimalScanner[string]{
+		nullable: s.nullable,
+		convert:  func(src string, cfg *config) (string, error) { return src, nil },
+	}
+}
+
+// Any returns a scanner of the untyped driver value, such as int64, float64, []byte,
+// string or time.Time, e.g. for columns whose type varies by row or database.
+func Any() AnyScanner[any] {
+	return DefaultScanner{nullable: notNull}.Any()
+}
+
+func (s DefaultScanner) Any() AnyScanner[any] {
+	return AnyScanner[any]{
+		nullable: s.nullable,
+		convert:  func(src any, cfg *config) (any, error) { return src, nil },
+	}
+}
+
+func To(path string) Destination {
+	return DefaultScanner{nullable: notNull}.To(path)
+}
+
+func (s DefaultScanner) To(path string) Destination {
+	return Destination{
+		path: path,
+		base: &s,
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			indices, key, dstType, err := destAccessor(typ, cfg.resolve(typ, path), cfg.depth())
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if err = nullCheck(cfg.nullMode(s.nullable), typ, indices, key); err != nil {
+				return nil, nil, categoryError{ErrNotAssignable, fmt.Errorf("path %s: %w", path, err)}
+			}
+
+			set, err := destSetter(dstType, cfg.factory, func(srcType reflect.Type) (func(dst, src reflect.Value) error, error) {
+				dstType = srcType
+
+				if cfg.trim && srcType.Kind() == reflect.String {
+					return trimValue, nil
+				}
+
+				return setValue, nil
+			})
+			if err != nil {
+				return nil, nil, categoryError{ErrNotAssignable, fmt.Errorf("path %s: %w", path, err)}
+			}
+
+			at := compileAccess(typ, indices)
+
+			if cfg.nullMode(s.nullable) != notNull {
+				var (
+					src   = reflect.New(reflect.PointerTo(dstType))
+					reset = nullReset(cfg.nullMode(s.nullable), typ, indices, key, cfg.prune)
+				)
+
+				return src.Interface(), func(dst reflect.Value) error {
+					elem := src.Elem()
+
+					if elem.IsNil() {
+						reset(dst)
+
+						return nil
+					}
+
+					return assign(dst, at, key, set, elem.Elem())
+				}, nil
+			}
+
+			src := reflect.New(dstType)
+
+			return src.Interface(), func(dst reflect.Value) error {
+				return assign(dst, at, key, set, src.Elem())
+			}, nil
+		},
+	}
+}
+
+// ToFunc calls fn, a func(dst *T, v V) error, with the value scanned into a new V
+// instead of setting a field by path, e.g. for custom assignment logic or field kinds
+// paths can't reach. The same applies to ToFunc on all scanners, which convert into V
+// as To does.
+func (s DefaultScanner) ToFunc(fn any) Scanner {
+	return Destination{
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			call, valType, err := funcDestination(typ, fn)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if cfg.nullMode(s.nullable) == nullNil {
+				if err = nilable(valType); err != nil {
+					return nil, nil, categoryError{ErrNotAssignable, err}
+				}
+			}
+
+			if cfg.nullMode(s.nullable) != notNull {
+				src := reflect.New(reflect.PointerTo(valType))
+
+				return src.Interface(), func(dst reflect.Value) error {
+					if src.Elem().IsNil() {
+						if mode := cfg.nullMode(s.nullable); mode == nullNil || mode == nullZero {
+							return call(dst, reflect.Zero(valType))
+						}
+
+						return nil
+					}
+
+					return call(dst, src.Elem().Elem())
+				}, nil
+			}
+
+			src := reflect.New(valType)
+
+			return src.Interface(), func(dst reflect.Value) error {
+				return call(dst, src.Elem())
+			}, nil
+		},
+	}
+}
+
+func (s DefaultScanner) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
 }
 
 type StringScanner[S any] struct {
-	nullable bool
-	convert  func(src S) (string, error)
+	nullable nullMode
+	convert  func(src S, cfg *config) (string, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error)
+}
+
+// Nullable makes the scanner scan NULL without setting the destination, like
+// DefaultScanner.Nullable. NULL is a property of the column, so converters earlier or
+// later in the chain are not called for it either, wherever Nullable appears.
+func (s StringScanner[S]) Nullable() StringScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter sets values with the setter fn returns for the destination type, if it
+// doesn't return an error, and with the scanner's own setter otherwise, e.g. to assign
+// to types the scanner doesn't support. Steps after WithSetter may discard it, so it
+// goes right before To or ToFunc.
+func (s StringScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error)) StringScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or converts values the scanner fails to convert with alt, a scanner of the same
+// source, e.g. String().ParseTime(time.RFC3339).Or(String().ParseTime(time.DateOnly)).
+func (s StringScanner[S]) Or(alt StringScanner[S]) StringScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError converts values the scanner fails to convert to def instead of failing the
+// scan, calling report, if any, with the source value and the error, e.g. to record
+// bad input of best-effort imports.
+func (s StringScanner[S]) OnError(def string, report ...func(src S, err error)) StringScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// If routes values for which pred is true through the chain of Then and the others
+// through the chain of Else, e.g. to parse numeric strings and look up names. NULL
+// columns go to Else, which like Then defaults to setting the value as it is.
+func (s StringScanner[S]) If(pred func(v string) bool) IfScanner[S, string, StringScanner[string]] {
+	root := DefaultScanner{nullable: s.nullable}.String()
+
+	return IfScanner[S, string, StringScanner[string]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
+
+// Convert converts the value with fn, a func(string) (W, error), e.g. for conversions
+// the scanner has no method for. The same applies to Convert on all scanners, which
+// pass the value they would set. To then requires a destination W is assignable to.
+// Prefer ConvertTo, which checks the signature of fn at compile time.
+func (s StringScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s StringScanner[S]) stage() stage[S, string] {
+	return stage[S, string]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s StringScanner[S]) ParseInt(base int, bitSize int) IntScanner[S] {
+	return IntScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			return strconv.ParseInt(val, base, bitSize)
+		},
+	}
+}
+
+func (s StringScanner[S]) ParseUint(base int, bitSize int) UintScanner[S] {
+	return UintScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (uint64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			return strconv.ParseUint(val, base, bitSize)
+		},
+	}
+}
+
+func (s StringScanner[S]) ParseFloat(bitSize int) FloatScanner[S] {
+	return FloatScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (float64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			return strconv.ParseFloat(val, bitSize)
+		},
+	}
+}
+
+func (s StringScanner[S]) ParseBool() BoolScanner[S] {
+	return BoolScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (bool, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return false, err
+			}
+
+			return strconv.ParseBool(val)
+		},
+	}
+}
+
+func (s StringScanner[S]) ParseTime(layout string) TimeScanner[S] {
+	return TimeScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (time.Time, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return time.Time{}, err
+			}
+
+			return parseTime(layout, val, cfg.timeLocation())
+		},
+	}
+}
+
+// parseTime parses val with layout, in loc if layout has no zone.
+func parseTime(layout, val string, loc *time.Location) (time.Time, error) {
+	if layoutHasZone(layout) {
+		return time.Parse(layout, val)
+	}
+
+	return time.ParseInLocation(layout, val, loc)
+}
+
+// zonedLayouts caches layoutHasZone by layout.
+var zonedLayouts sync.Map
+
+// layoutHasZone reports whether layout contains a zone, by formatting a time in a
+// zone with an offset and abbreviation and checking that parsing it in UTC restores
+// a zone other than UTC.
+func layoutHasZone(layout string) bool {
+	if cached, ok := zonedLayouts.Load(layout); ok {
+		//nolint:forcetypeassert
+		return cached.(bool)
+	}
+
+	probe := time.Date(2001, 2, 3, 4, 5, 6, 0, time.FixedZone("PRB", 5*3600+30*60))
+
+	parsed, err := time.ParseInLocation(layout, probe.Format(layout), time.UTC)
+	zoned := err == nil && parsed.Location() != time.UTC
+
+	zonedLayouts.Store(layout, zoned)
+
+	return zoned
+}
+
+// trimmed makes convert trim white space from strings if trim is set.
+func trimmed[S, C any](convert func(src S, cfg *config) (C, error), trim bool) func(src S, cfg *config) (C, error) {
+	if !trim || reflect.TypeFor[C]() != stringType {
+		return convert
+	}
+
+	return func(src S, cfg *config) (C, error) {
+		conv, err := convert(src, cfg)
+
+		//nolint:forcetypeassert
+		s := any(&conv).(*string)
+		*s = strings.TrimSpace(*s)
+
+		return conv, err
+	}
+}
+
+func (s StringScanner[S]) ParseTimeInLocation(layout string, loc *time.Location) TimeScanner[S] {
+	return TimeScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (time.Time, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return time.Time{}, err
+			}
+
+			return time.ParseInLocation(layout, val, loc)
+		},
+	}
+}
+
+func (s StringScanner[S]) Trim(cutset string) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.Trim(val, cutset), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) TrimSpace() StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.TrimSpace(val), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) TrimPrefix(prefix string) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.TrimPrefix(val, prefix), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) TrimSuffix(suffix string) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.TrimSuffix(val, suffix), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) ToLower() StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.ToLower(val), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) ToUpper() StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.ToUpper(val), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) Replace(old, new string, n int) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.Replace(val, old, new, n), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) ReplaceAll(old, new string) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strings.ReplaceAll(val, old, new), nil
+		},
+	}
+}
+
+// Transform applies fn to the value. It is the hook for x/text transformers,
+// e.g. accent folding, without this package depending on golang.org/x/text:
+//
+//	Scan().String().Transform(func(s string) (string, error) {
+//		t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+//		s, _, err := transform.String(t, s)
+//		return s, err
+//	})
+//
+// Transformers are stateful, so build a new one per call as above.
+func (s StringScanner[S]) Transform(fn func(v string) (string, error)) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return fn(val)
+		},
+	}
+}
+
+// Canonicalize replaces the value with its canonical form as fn returns it, failing
+// the conversion for values fn rejects. It is the hook for normalizing libraries of
+// phone numbers, IBANs and the like:
+//
+//	Scan().String().Canonicalize(func(s string) (string, error) {
+//		num, err := phonenumbers.Parse(s, "DE")
+//		if err != nil {
+//			return "", err
+//		}
+//		return phonenumbers.Format(num, phonenumbers.E164), nil
+//	})
+//
+// Unlike Transform, New fails if fn is nil, and errors include the rejected value.
+func (s StringScanner[S]) Canonicalize(fn func(v string) (string, error)) StringScanner[S] {
+	var err error
+
+	if fn == nil {
+		err = errors.New("canonicalize: nil func")
+	}
+
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      errors.Join(s.err, err),
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			canonical, err := fn(val)
+			if err != nil {
+				return "", fmt.Errorf("canonicalize %q: %w", val, err)
+			}
+
+			return canonical, nil
+		},
+	}
+}
+
+// MaxRunes truncates the value to at most n runes.
+func (s StringScanner[S]) MaxRunes(n int) StringScanner[S] {
+	var err error
+
+	if n < 0 {
+		err = fmt.Errorf("max runes: invalid limit %d", n)
+	}
+
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      errors.Join(s.err, err),
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			if len(val) <= n {
+				return val, nil
+			}
+
+			var count int
+
+			for i := range val {
+				if count == n {
+					return val[:i], nil
+				}
+
+				count++
+			}
+
+			return val, nil
+		},
+	}
+}
+
+// MaxBytes truncates the value to at most n bytes without splitting a rune.
+func (s StringScanner[S]) MaxBytes(n int) StringScanner[S] {
+	var err error
+
+	if n < 0 {
+		err = fmt.Errorf("max bytes: invalid limit %d", n)
+	}
+
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      errors.Join(s.err, err),
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			if len(val) <= n {
+				return val, nil
+			}
+
+			i := n
+			for i > 0 && !utf8.RuneStart(val[i]) {
+				i--
+			}
+
+			return val[:i], nil
+		},
+	}
+}
+
+// Mask replaces every rune except the first keepPrefix and last keepSuffix runes
+// with maskRune, e.g. Mask(0, 4, '*') for card numbers. Values too short to keep
+// both ends are masked entirely.
+func (s StringScanner[S]) Mask(keepPrefix, keepSuffix int, maskRune rune) StringScanner[S] {
+	var err error
+
+	if keepPrefix < 0 || keepSuffix < 0 {
+		err = fmt.Errorf("mask: invalid prefix %d or suffix %d", keepPrefix, keepSuffix)
+	}
+
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      errors.Join(s.err, err),
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			runes := []rune(val)
+
+			for i := range runes {
+				if len(runes) > keepPrefix+keepSuffix && (i < keepPrefix || i >= len(runes)-keepSuffix) {
+					continue
+				}
+
+				runes[i] = maskRune
+			}
+
+			return string(runes), nil
+		},
+	}
+}
+
+type Enum struct {
+	String string
+	Int    int64
+}
+
+func (s StringScanner[S]) Enum(enums ...Enum) IntScanner[S] {
+	return IntScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (int64, error) {
+			conv, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			for _, each := range enums {
+				if each.String == conv {
+					return each.Int, nil
+				}
+			}
+
+			return 0, fmt.Errorf("value %s is not one of enums: %v", conv, enums)
+		},
+	}
+}
+
+func (s StringScanner[S]) Split(sep string) StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			if val == "" {
+				return []string{}, nil
+			}
+
+			return strings.Split(val, sep), nil
+		},
+	}
+}
+
+// SplitN splits into at most n parts like strings.SplitN, the last part holding the
+// unsplit remainder, e.g. "code rest of description" into a code and a description.
+func (s StringScanner[S]) SplitN(sep string, n int) StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			if val == "" {
+				return []string{}, nil
+			}
+
+			return strings.SplitN(val, sep, n), nil
+		},
+	}
+}
+
+func (s StringScanner[S]) Fields() StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return strings.Fields(val), nil
+		},
+	}
+}
+
+// SplitRegexp splits around the matches of pattern, e.g. `\s+`. The pattern is compiled
+// once, an invalid pattern is reported by New.
+func (s StringScanner[S]) SplitRegexp(pattern string) StringSliceScanner[S] {
+	re, err := regexp.Compile(pattern)
+
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      errors.Join(s.err, err),
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			if val == "" {
+				return []string{}, nil
+			}
+
+			return re.Split(val, -1), nil
+		},
+	}
+}
+
+// ParseKV parses pairs like "a=1;b=2" into a map, trimming spaces around keys and
+// values and ignoring empty pairs. See StringMapScanner for supported destinations.
+func (s StringScanner[S]) ParseKV(pairSep, kvSep string) StringMapScanner[S] {
+	return StringMapScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (map[string]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			conv := map[string]string{}
+
+			for pair := range strings.SplitSeq(val, pairSep) {
+				if strings.TrimSpace(pair) == "" {
+					continue
+				}
+
+				key, value, ok := strings.Cut(pair, kvSep)
+				if !ok {
+					return nil, fmt.Errorf("missing %q in pair %q", kvSep, pair)
+				}
+
+				conv[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+
+			return conv, nil
+		},
+	}
+}
+
+func (s StringScanner[S]) ParseQuery() ValuesScanner[S] {
+	return ValuesScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (url.Values, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return url.ParseQuery(val)
+		},
+	}
+}
+
+// ParseURL parses the value with url.Parse into a URL, which may be set to url.URL
+// and *url.URL fields and restricted with Schemes and RequireHost.
+func (s StringScanner[S]) ParseURL() URLScanner[S] {
+	return URLScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (*url.URL, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return url.Parse(val)
+		},
+	}
+}
+
+// ParseCIDR parses the value with netip.ParsePrefix, e.g. "10.0.0.0/8", into a
+// prefix, which may be set to netip.Prefix and net.IPNet fields.
+func (s StringScanner[S]) ParseCIDR() PrefixScanner[S] {
+	return PrefixScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (netip.Prefix, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return netip.Prefix{}, err
+			}
+
+			return netip.ParsePrefix(val)
+		},
+	}
+}
+
+// ParseIPRange parses ranges of IP addresses of the same family, e.g.
+// "10.0.0.1-10.0.0.99", which may be set to structs of two netip.Addr or net.IP fields
+// taking the first and last address in order.
+func (s StringScanner[S]) ParseIPRange() IPRangeScanner[S] {
+	return IPRangeScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([2]netip.Addr, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return [2]netip.Addr{}, err
+			}
+
+			first, last, ok := strings.Cut(val, "-")
+			if !ok {
+				return [2]netip.Addr{}, fmt.Errorf("ip range %q: missing \"-\"", val)
+			}
+
+			var addrs [2]netip.Addr
+
+			for i, addr := range []string{first, last} {
+				if addrs[i], err = netip.ParseAddr(strings.TrimSpace(addr)); err != nil {
+					return [2]netip.Addr{}, err
+				}
+			}
+
+			if addrs[0].BitLen() != addrs[1].BitLen() || addrs[1].Less(addrs[0]) {
+				return [2]netip.Addr{}, fmt.Errorf("ip range %q: invalid bounds", val)
+			}
+
+			return addrs, nil
+		},
+	}
+}
+
+// ParseCSV splits a single CSV record using encoding/csv semantics, so that quoted
+// fields may contain the separator, quotes or newlines.
+func (s StringScanner[S]) ParseCSV(comma rune) StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			if val == "" {
+				return []string{}, nil
+			}
+
+			reader := csv.NewReader(strings.NewReader(val))
+			reader.Comma = comma
+			reader.FieldsPerRecord = -1
+
+			record, err := reader.Read()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err = reader.Read(); !errors.Is(err, io.EOF) {
+				return nil, errors.New("value contains more than one csv record")
+			}
+
+			return record, nil
+		},
+	}
+}
+
+func (s StringScanner[S]) Decimal() DecimalScanner[S] {
+	return DecimalScanner[S](s)
+}
+
+func (s StringScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s StringScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s StringScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var stringType = reflect.TypeFor[string]()
+
+func (s StringScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error) {
+	if dstType == stringType {
+		return func(dst reflect.Value, conv string) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*string) = conv
+
+			return nil
+		}, nil
+	}
+
+	if dstType.Kind() == reflect.String {
+		return func(dst reflect.Value, conv string) error {
+			dst.SetString(conv)
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to string value", dstType)
+}
+
+type IntScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (int64, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv int64) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s IntScanner[S]) Nullable() IntScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s IntScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv int64) error, error)) IntScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s IntScanner[S]) Or(alt IntScanner[S]) IntScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s IntScanner[S]) OnError(def int64, report ...func(src S, err error)) IntScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// If is like StringScanner.If.
+func (s IntScanner[S]) If(pred func(v int64) bool) IfScanner[S, int64, IntScanner[int64]] {
+	root := DefaultScanner{nullable: s.nullable}.Int()
+
+	return IfScanner[S, int64, IntScanner[int64]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
+
+// Convert is like StringScanner.Convert for a func(int64) (W, error).
+func (s IntScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s IntScanner[S]) stage() stage[S, int64] {
+	return stage[S, int64]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s IntScanner[S]) Format(base int) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strconv.FormatInt(val, base), nil
+		},
+	}
+}
+
+func (s IntScanner[S]) Enum(enums ...Enum) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			conv, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			for _, each := range enums {
+				if each.Int == conv {
+					return each.String, nil
+				}
+			}
+
+			return "", fmt.Errorf("value %d is not one of enums: %v", conv, enums)
+		},
+	}
+}
+
+// Flags expands bitmasks into the names of the flags they contain in ascending order
+// of the flag values, e.g. 5 into ["read", "exec"] for
+// map[int64]string{1: "read", 2: "write", 4: "exec"}. Bits not covered by a flag fail
+// the conversion.
+func (s IntScanner[S]) Flags(flags map[int64]string) StringSliceScanner[S] {
+	var (
+		masks = slices.Sorted(maps.Keys(flags))
+		err   error
+	)
+
+	if slices.Contains(masks, 0) {
+		err = errors.New("flags: invalid flag value 0")
+	}
+
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      errors.Join(s.err, err),
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			var (
+				names = []string{}
+				rest  = val
+			)
+
+			for _, mask := range masks {
+				if val&mask == mask {
+					names = append(names, flags[mask])
+					rest &^= mask
+				}
+			}
+
+			if rest != 0 {
+				return nil, fmt.Errorf("value %d has unknown flags %#x", val, rest)
+			}
+
+			return names, nil
+		},
+	}
+}
+
+// Bits expands bitmasks into the values of their set bits in ascending order, e.g. 5
+// into [1, 4], which may be set to slices of typed flag constants.
+func (s IntScanner[S]) Bits() IntSliceScanner[S] {
+	return IntSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			bits := []int64{}
+
+			for bit := int64(1); bit != 0; bit <<= 1 {
+				if val&bit != 0 {
+					bits = append(bits, bit)
+				}
+			}
+
+			return bits, nil
+		},
+	}
+}
+
+func (s IntScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s IntScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s IntScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var intType = reflect.TypeFor[int64]()
+
+func (s IntScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv int64) error, error) {
+	if dstType == intType {
+		return func(dst reflect.Value, conv int64) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*int64) = conv
+
+			return nil
+		}, nil
+	}
+
+	//nolint:exhaustive
+	switch dstType.Kind() {
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		return func(dst reflect.Value, conv int64) error {
+			if dst.OverflowInt(conv) {
+				return fmt.Errorf("overflow of int64 value %d to %s", conv, dstType)
+			}
+
+			dst.SetInt(conv)
+
+			return nil
+		}, nil
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		return func(dst reflect.Value, conv int64) error {
+			if conv < 0 {
+				return fmt.Errorf("lossy conversion of int64 value %d to %s", conv, dstType)
+			}
+
+			v := uint64(conv)
+
+			if dst.OverflowUint(v) {
+				return fmt.Errorf("overflow of int64 value %d to %s", conv, dstType)
+			}
+
+			dst.SetUint(v)
+
+			return nil
+		}, nil
+	case reflect.Float64, reflect.Float32:
+		return func(dst reflect.Value, conv int64) error {
+			v := float64(conv)
+
+			if dst.OverflowFloat(v) {
+				return fmt.Errorf("overflow of int64 value %d to %s", conv, dstType)
+			}
+
+			dst.SetFloat(v)
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to int64 value", dstType)
+}
+
+type UintScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (uint64, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv uint64) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s UintScanner[S]) Nullable() UintScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s UintScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv uint64) error, error)) UintScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s UintScanner[S]) Or(alt UintScanner[S]) UintScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s UintScanner[S]) OnError(def uint64, report ...func(src S, err error)) UintScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// If is like StringScanner.If.
+func (s UintScanner[S]) If(pred func(v uint64) bool) IfScanner[S, uint64, UintScanner[uint64]] {
+	root := DefaultScanner{nullable: s.nullable}.Uint()
+
+	return IfScanner[S, uint64, UintScanner[uint64]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
+
+// Convert is like StringScanner.Convert for a func(uint64) (W, error).
+func (s UintScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s UintScanner[S]) stage() stage[S, uint64] {
+	return stage[S, uint64]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s UintScanner[S]) Format(base int) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strconv.FormatUint(val, base), nil
+		},
+	}
+}
+
+func (s UintScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s UintScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s UintScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var uint64Type = reflect.TypeFor[uint64]()
+
+func (s UintScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv uint64) error, error) {
+	if dstType == uint64Type {
+		return func(dst reflect.Value, conv uint64) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*uint64) = conv
+
+			return nil
+		}, nil
+	}
+
+	//nolint:exhaustive
+	switch dstType.Kind() {
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		return func(dst reflect.Value, conv uint64) error {
+			if dst.OverflowUint(conv) {
+				return fmt.Errorf("overflow of uint64 value %d to %s", conv, dstType)
+			}
+
+			dst.SetUint(conv)
+
+			return nil
+		}, nil
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		return func(dst reflect.Value, conv uint64) error {
+			if conv > math.MaxInt64 {
+				return fmt.Errorf("lossy conversion of uint64 value %d to %s", conv, dstType)
+			}
+
+			v := int64(conv)
+
+			if dst.OverflowInt(v) {
+				return fmt.Errorf("overflow of uint64 value %d to %s", conv, dstType)
+			}
+
+			dst.SetInt(v)
+
+			return nil
+		}, nil
+	case reflect.Float64, reflect.Float32:
+		return func(dst reflect.Value, conv uint64) error {
+			v := float64(conv)
+
+			if dst.OverflowFloat(v) {
+				return fmt.Errorf("overflow of uint64 value %d to %s", conv, dstType)
+			}
+
+			dst.SetFloat(v)
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to uint64 value", dstType)
+}
+
+type FloatScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (float64, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv float64) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s FloatScanner[S]) Nullable() FloatScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s FloatScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv float64) error, error)) FloatScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s FloatScanner[S]) Or(alt FloatScanner[S]) FloatScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s FloatScanner[S]) OnError(def float64, report ...func(src S, err error)) FloatScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// If is like StringScanner.If.
+func (s FloatScanner[S]) If(pred func(v float64) bool) IfScanner[S, float64, FloatScanner[float64]] {
+	root := DefaultScanner{nullable: s.nullable}.Float()
+
+	return IfScanner[S, float64, FloatScanner[float64]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
+
+// Convert is like StringScanner.Convert for a func(float64) (W, error).
+func (s FloatScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s FloatScanner[S]) stage() stage[S, float64] {
+	return stage[S, float64]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s FloatScanner[S]) Format(fmt byte, prec int, bitSize int) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strconv.FormatFloat(val, fmt, prec, bitSize), nil
+		},
+	}
+}
+
+func (s FloatScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s FloatScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s FloatScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var float64Type = reflect.TypeFor[float64]()
+
+func (s FloatScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv float64) error, error) {
+	if dstType == float64Type {
+		return func(dst reflect.Value, conv float64) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*float64) = conv
+
+			return nil
+		}, nil
+	}
+
+	//nolint:exhaustive
+	switch dstType.Kind() {
+	case reflect.Float64, reflect.Float32:
+		return func(dst reflect.Value, conv float64) error {
+			dst.SetFloat(conv)
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to float64 value", dstType)
+}
+
+type BoolScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (bool, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv bool) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s BoolScanner[S]) Nullable() BoolScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s BoolScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv bool) error, error)) BoolScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s BoolScanner[S]) Or(alt BoolScanner[S]) BoolScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s BoolScanner[S]) OnError(def bool, report ...func(src S, err error)) BoolScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// If is like StringScanner.If.
+func (s BoolScanner[S]) If(pred func(v bool) bool) IfScanner[S, bool, BoolScanner[bool]] {
+	root := DefaultScanner{nullable: s.nullable}.Bool()
+
+	return IfScanner[S, bool, BoolScanner[bool]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
+
+// Convert is like StringScanner.Convert for a func(bool) (W, error).
+func (s BoolScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s BoolScanner[S]) stage() stage[S, bool] {
+	return stage[S, bool]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s BoolScanner[S]) Format() StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return strconv.FormatBool(val), nil
+		},
+	}
+}
+
+func (s BoolScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s BoolScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s BoolScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var boolType = reflect.TypeFor[bool]()
+
+func (s BoolScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv bool) error, error) {
+	if dstType == boolType {
+		return func(dst reflect.Value, conv bool) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*bool) = conv
+
+			return nil
+		}, nil
+	}
+
+	if dstType.Kind() == reflect.Bool {
+		return func(dst reflect.Value, conv bool) error {
+			dst.SetBool(conv)
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to bool value", dstType)
+}
+
+type TimeScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (time.Time, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv time.Time) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s TimeScanner[S]) Nullable() TimeScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s TimeScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv time.Time) error, error)) TimeScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s TimeScanner[S]) Or(alt TimeScanner[S]) TimeScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s TimeScanner[S]) OnError(def time.Time, report ...func(src S, err error)) TimeScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// If is like StringScanner.If.
+func (s TimeScanner[S]) If(pred func(v time.Time) bool) IfScanner[S, time.Time, TimeScanner[time.Time]] {
+	root := DefaultScanner{nullable: s.nullable}.Time()
+
+	return IfScanner[S, time.Time, TimeScanner[time.Time]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
+
+// Convert is like StringScanner.Convert for a func(time.Time) (W, error).
+func (s TimeScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s TimeScanner[S]) stage() stage[S, time.Time] {
+	return stage[S, time.Time]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s TimeScanner[S]) Format(layout string) StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return "", err
+			}
+
+			return val.Format(layout), nil
+		},
+	}
+}
+
+func (s TimeScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s TimeScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s TimeScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func (s TimeScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv time.Time) error, error) {
+	if dstType == timeType {
+		return func(dst reflect.Value, conv time.Time) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*time.Time) = conv
+
+			return nil
+		}, nil
+	}
+
+	if timeType.ConvertibleTo(dstType) {
+		return func(dst reflect.Value, conv time.Time) error {
+			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to time.Time value", dstType)
+}
+
+type BytesScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([]byte, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s BytesScanner[S]) Nullable() BytesScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s BytesScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)) BytesScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s BytesScanner[S]) Or(alt BytesScanner[S]) BytesScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s BytesScanner[S]) OnError(def []byte, report ...func(src S, err error)) BytesScanner[S] {
+	s.convert = defaultConvert(s.convert, def, bytes.Clone, report)
+
+	return s
+}
+
+// If is like StringScanner.If.
+func (s BytesScanner[S]) If(pred func(v []byte) bool) IfScanner[S, []byte, BytesScanner[[]byte]] {
+	root := DefaultScanner{nullable: s.nullable}.Bytes()
+
+	return IfScanner[S, []byte, BytesScanner[[]byte]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
+
+// Convert is like StringScanner.Convert for a func([]byte) (W, error).
+func (s BytesScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s BytesScanner[S]) stage() stage[S, []byte] {
+	return stage[S, []byte]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s BytesScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s BytesScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s BytesScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var bytesType = reflect.TypeFor[[]byte]()
+
+func (s BytesScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
+	if dstType == bytesType {
+		return func(dst reflect.Value, conv []byte) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*[]byte) = conv
+
+			return nil
+		}, nil
+	}
+
+	if dstType == jsonMessageType {
+		return func(dst reflect.Value, conv []byte) error {
+			dst.SetBytes(conv)
+
+			return nil
+		}, nil
+	}
+
+	if bytesType.ConvertibleTo(dstType) {
+		return func(dst reflect.Value, conv []byte) error {
+			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to []byte value", dstType)
+}
+
+type StringSliceScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([]string, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv []string) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s StringSliceScanner[S]) Nullable() StringSliceScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s StringSliceScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []string) error, error)) StringSliceScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s StringSliceScanner[S]) Or(alt StringSliceScanner[S]) StringSliceScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s StringSliceScanner[S]) OnError(def []string, report ...func(src S, err error)) StringSliceScanner[S] {
+	s.convert = defaultConvert(s.convert, def, slices.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]string) (W, error).
+func (s StringSliceScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s StringSliceScanner[S]) stage() stage[S, []string] {
+	return stage[S, []string]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s StringSliceScanner[S]) Asc() StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			slices.Sort(val)
+
+			return val, nil
+		},
+	}
+}
+
+func (s StringSliceScanner[S]) Desc() StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			slices.Sort(val)
+			slices.Reverse(val)
+
+			return val, nil
+		},
+	}
+}
+
+func (s StringSliceScanner[S]) TrimSpaceEach() StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, v := range val {
+				val[i] = strings.TrimSpace(v)
+			}
+
+			return val, nil
+		},
+	}
+}
+
+func (s StringSliceScanner[S]) Map(fn func(v string) (string, error)) StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, v := range val {
+				if val[i], err = fn(v); err != nil {
+					return nil, err
+				}
+			}
+
+			return val, nil
+		},
+	}
+}
+
+func (s StringSliceScanner[S]) Filter(fn func(v string) bool) StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return slices.DeleteFunc(val, func(v string) bool { return !fn(v) }), nil
+		},
+	}
+}
+
+// Unique removes duplicates, keeping the first occurrence of each value.
+func (s StringSliceScanner[S]) Unique() StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			seen := make(map[string]struct{}, len(val))
+
+			return slices.DeleteFunc(val, func(v string) bool {
+				if _, ok := seen[v]; ok {
+					return true
+				}
+
+				seen[v] = struct{}{}
+
+				return false
+			}), nil
+		},
+	}
+}
+
+// Compact removes empty values, e.g. produced by splitting "a,,b" on ",".
+func (s StringSliceScanner[S]) Compact() StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return slices.DeleteFunc(val, func(v string) bool { return v == "" }), nil
+		},
+	}
+}
+
+func (s StringSliceScanner[S]) ParseInt(base int, bitSize int) IntSliceScanner[S] {
+	return IntSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			conv := make([]int64, len(val))
+
+			for i, v := range val {
+				c, err := strconv.ParseInt(v, base, bitSize)
+				if err != nil {
+					return nil, err
+				}
+
+				conv[i] = c
+			}
+
+			return conv, nil
+		},
+	}
+}
+
+func (s StringSliceScanner[S]) ParseUint(base int, bitSize int) UintSliceScanner[S] {
+	return UintSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]uint64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			conv := make([]uint64, len(val))
+
+			for i, v := range val {
+				c, err := strconv.ParseUint(v, base, bitSize)
+				if err != nil {
+					return nil, err
+				}
+
+				conv[i] = c
+			}
+
+			return conv, nil
+		},
+	}
+}
+
+func (s StringSliceScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s StringSliceScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s StringSliceScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var stringSliceType = reflect.TypeFor[[]string]()
+
+func (s StringSliceScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []string) error, error) {
+	if dstType == stringSliceType {
+		return func(dst reflect.Value, conv []string) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*[]string) = conv
+
+			return nil
+		}, nil
+	}
+
+	if stringSliceType.ConvertibleTo(dstType) {
+		return func(dst reflect.Value, conv []string) error {
+			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+
+			return nil
+		}, nil
+	}
+
+	if dstType.Kind() == reflect.Slice && derefType(dstType.Elem()).Kind() == reflect.String {
+		return func(dst reflect.Value, conv []string) error {
+			dst.Set(reflect.MakeSlice(dstType, len(conv), len(conv)))
+
+			for i, v := range conv {
+				deref(dst.Index(i)).SetString(v)
+			}
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to []string value", dstType)
+}
+
+type IntSliceScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([]int64, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv []int64) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s IntSliceScanner[S]) Nullable() IntSliceScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s IntSliceScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []int64) error, error)) IntSliceScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s IntSliceScanner[S]) Or(alt IntSliceScanner[S]) IntSliceScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s IntSliceScanner[S]) OnError(def []int64, report ...func(src S, err error)) IntSliceScanner[S] {
+	s.convert = defaultConvert(s.convert, def, slices.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]int64) (W, error).
+func (s IntSliceScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s IntSliceScanner[S]) stage() stage[S, []int64] {
+	return stage[S, []int64]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s IntSliceScanner[S]) Asc() IntSliceScanner[S] {
+	return IntSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			slices.Sort(val)
+
+			return val, nil
+		},
+	}
+}
+
+func (s IntSliceScanner[S]) Desc() IntSliceScanner[S] {
+	return IntSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			slices.Sort(val)
+			slices.Reverse(val)
+
+			return val, nil
+		},
+	}
+}
+
+func (s IntSliceScanner[S]) Sum() IntScanner[S] {
+	return IntScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			var sum int64
+
+			for _, v := range val {
+				if (v > 0 && sum > math.MaxInt64-v) || (v < 0 && sum < math.MinInt64-v) {
+					return 0, fmt.Errorf("overflow of int64 sum of %v", val)
+				}
+
+				sum += v
+			}
+
+			return sum, nil
+		},
+	}
+}
+
+func (s IntSliceScanner[S]) Min() IntScanner[S] {
+	return IntScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			if len(val) == 0 {
+				return 0, errors.New("min of empty int64 slice")
+			}
+
+			return slices.Min(val), nil
+		},
+	}
+}
+
+func (s IntSliceScanner[S]) Max() IntScanner[S] {
+	return IntScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			if len(val) == 0 {
+				return 0, errors.New("max of empty int64 slice")
+			}
+
+			return slices.Max(val), nil
+		},
+	}
+}
+
+func (s IntSliceScanner[S]) Len() IntScanner[S] {
+	return IntScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (int64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return 0, err
+			}
+
+			return int64(len(val)), nil
+		},
+	}
+}
+
+func (s IntSliceScanner[S]) Format(base int) StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			conv := make([]string, len(val))
+
+			for i, v := range val {
+				conv[i] = strconv.FormatInt(v, base)
+			}
+
+			return conv, nil
+		},
+	}
+}
+
+func (s IntSliceScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s IntSliceScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s IntSliceScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var int64SliceType = reflect.TypeFor[[]int64]()
+
+func (s IntSliceScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []int64) error, error) {
+	if dstType == int64SliceType {
+		return func(dst reflect.Value, conv []int64) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*[]int64) = conv
+
+			return nil
+		}, nil
+	}
+
+	if int64SliceType.ConvertibleTo(dstType) {
+		return func(dst reflect.Value, conv []int64) error {
+			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+
+			return nil
+		}, nil
+	}
+
+	if dstType.Kind() == reflect.Slice {
+		//nolint:exhaustive
+		switch derefType(dstType.Elem()).Kind() {
+		case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+			return func(dst reflect.Value, conv []int64) error {
+				dst.Set(reflect.MakeSlice(dstType, len(conv), len(conv)))
+
+				for i, v := range conv {
+					deref(dst.Index(i)).SetInt(v)
+				}
+
+				return nil
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to []int64 value", dstType)
+}
+
+type UintSliceScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([]uint64, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv []uint64) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s UintSliceScanner[S]) Nullable() UintSliceScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s UintSliceScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []uint64) error, error)) UintSliceScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s UintSliceScanner[S]) Or(alt UintSliceScanner[S]) UintSliceScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s UintSliceScanner[S]) OnError(def []uint64, report ...func(src S, err error)) UintSliceScanner[S] {
+	s.convert = defaultConvert(s.convert, def, slices.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]uint64) (W, error).
+func (s UintSliceScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s UintSliceScanner[S]) stage() stage[S, []uint64] {
+	return stage[S, []uint64]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s UintSliceScanner[S]) Asc() UintSliceScanner[S] {
+	return UintSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]uint64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			slices.Sort(val)
+
+			return val, nil
+		},
+	}
+}
+
+func (s UintSliceScanner[S]) Desc() UintSliceScanner[S] {
+	return UintSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]uint64, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			slices.Sort(val)
+			slices.Reverse(val)
+
+			return val, nil
+		},
+	}
+}
+
+func (s UintSliceScanner[S]) Format(base int) StringSliceScanner[S] {
+	return StringSliceScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) ([]string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			conv := make([]string, len(val))
+
+			for i, v := range val {
+				conv[i] = strconv.FormatUint(v, base)
+			}
+
+			return conv, nil
+		},
+	}
+}
+
+func (s UintSliceScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s UintSliceScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s UintSliceScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var uint64SliceType = reflect.TypeFor[[]uint64]()
+
+func (s UintSliceScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []uint64) error, error) {
+	if dstType == uint64SliceType {
+		return func(dst reflect.Value, conv []uint64) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*[]uint64) = conv
+
+			return nil
+		}, nil
+	}
+
+	if uint64SliceType.ConvertibleTo(dstType) {
+		return func(dst reflect.Value, conv []uint64) error {
+			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+
+			return nil
+		}, nil
+	}
+
+	if dstType.Kind() == reflect.Slice {
+		//nolint:exhaustive
+		switch derefType(dstType.Elem()).Kind() {
+		case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+			return func(dst reflect.Value, conv []uint64) error {
+				dst.Set(reflect.MakeSlice(dstType, len(conv), len(conv)))
+
+				for i, v := range conv {
+					elem := deref(dst.Index(i))
+
+					if elem.OverflowUint(v) {
+						return fmt.Errorf("overflow of uint64 value %d to %s", v, elem.Type())
+					}
+
+					elem.SetUint(v)
+				}
+
+				return nil
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to []uint64 value", dstType)
+}
+
+// StringMapScanner assigns a map[string]string. Destinations with string keys and
+// int, uint, float or bool values are supported as well, parsing each value with strconv.
+type StringMapScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (map[string]string, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv map[string]string) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s StringMapScanner[S]) Nullable() StringMapScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s StringMapScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv map[string]string) error, error)) StringMapScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s StringMapScanner[S]) Or(alt StringMapScanner[S]) StringMapScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s StringMapScanner[S]) OnError(def map[string]string, report ...func(src S, err error)) StringMapScanner[S] {
+	s.convert = defaultConvert(s.convert, def, maps.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func(map[string]string) (W, error).
+func (s StringMapScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s StringMapScanner[S]) stage() stage[S, map[string]string] {
+	return stage[S, map[string]string]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s StringMapScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s StringMapScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s StringMapScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var stringMapType = reflect.TypeFor[map[string]string]()
+
+func (s StringMapScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv map[string]string) error, error) {
+	if dstType == stringMapType {
+		return func(dst reflect.Value, conv map[string]string) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*map[string]string) = conv
+
+			return nil
+		}, nil
+	}
+
+	if dstType.Kind() != reflect.Map || dstType.Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("%s is not assignable to map[string]string value", dstType)
+	}
+
+	var (
+		elemType = dstType.Elem()
+		parse    func(elem reflect.Value, v string) error
+	)
+
+	//nolint:exhaustive
+	switch elemType.Kind() {
+	case reflect.String:
+		parse = func(elem reflect.Value, v string) error {
+			elem.SetString(v)
+
+			return nil
+		}
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		parse = func(elem reflect.Value, v string) error {
+			i, err := strconv.ParseInt(v, 10, elemType.Bits())
+			if err != nil {
+				return err
+			}
+
+			elem.SetInt(i)
+
+			return nil
+		}
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		parse = func(elem reflect.Value, v string) error {
+			u, err := strconv.ParseUint(v, 10, elemType.Bits())
+			if err != nil {
+				return err
+			}
+
+			elem.SetUint(u)
+
+			return nil
+		}
+	case reflect.Float64, reflect.Float32:
+		parse = func(elem reflect.Value, v string) error {
+			f, err := strconv.ParseFloat(v, elemType.Bits())
+			if err != nil {
+				return err
+			}
+
+			elem.SetFloat(f)
+
+			return nil
+		}
+	case reflect.Bool:
+		parse = func(elem reflect.Value, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+
+			elem.SetBool(b)
+
+			return nil
+		}
+	default:
+		return nil, fmt.Errorf("%s is not assignable to map[string]string value", dstType)
+	}
+
+	return func(dst reflect.Value, conv map[string]string) error {
+		m := reflect.MakeMapWithSize(dstType, len(conv))
+		elem := reflect.New(elemType).Elem()
+
+		for k, v := range conv {
+			if err := parse(elem, v); err != nil {
+				return fmt.Errorf("key %s: %w", k, err)
+			}
+
+			m.SetMapIndex(reflect.ValueOf(k).Convert(dstType.Key()), elem)
+		}
+
+		dst.Set(m)
+
+		return nil
+	}, nil
+}
+
+type ValuesScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (url.Values, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv url.Values) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s ValuesScanner[S]) Nullable() ValuesScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s ValuesScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv url.Values) error, error)) ValuesScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s ValuesScanner[S]) Or(alt ValuesScanner[S]) ValuesScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s ValuesScanner[S]) OnError(def url.Values, report ...func(src S, err error)) ValuesScanner[S] {
+	s.convert = defaultConvert(s.convert, def, cloneValues, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func(url.Values) (W, error).
+func (s ValuesScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s ValuesScanner[S]) stage() stage[S, url.Values] {
+	return stage[S, url.Values]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s ValuesScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s ValuesScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s ValuesScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var valuesType = reflect.TypeFor[url.Values]()
+
+func (s ValuesScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv url.Values) error, error) {
+	if dstType == valuesType {
+		return func(dst reflect.Value, conv url.Values) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*url.Values) = conv
+
+			return nil
+		}, nil
+	}
+
+	if valuesType.ConvertibleTo(dstType) {
+		return func(dst reflect.Value, conv url.Values) error {
+			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to url.Values value", dstType)
+}
+
+type URLScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (*url.URL, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv *url.URL) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s URLScanner[S]) Nullable() URLScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s URLScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv *url.URL) error, error)) URLScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s URLScanner[S]) Or(alt URLScanner[S]) URLScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s URLScanner[S]) OnError(def *url.URL, report ...func(src S, err error)) URLScanner[S] {
+	s.convert = defaultConvert(s.convert, def, cloneURL, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func(*url.URL) (W, error).
+func (s URLScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s URLScanner[S]) stage() stage[S, *url.URL] {
+	return stage[S, *url.URL]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+// Schemes fails the conversion of URLs with a scheme other than the given ones,
+// compared ignoring case.
+func (s URLScanner[S]) Schemes(schemes ...string) URLScanner[S] {
+	return s.check(func(u *url.URL) error {
+		if !slices.ContainsFunc(schemes, func(scheme string) bool { return strings.EqualFold(scheme, u.Scheme) }) {
+			return fmt.Errorf("url %s: scheme %q is not one of %q", u.Redacted(), u.Scheme, schemes)
+		}
+
+		return nil
+	})
+}
+
+// RequireHost fails the conversion of URLs without a host, such as relative ones.
+func (s URLScanner[S]) RequireHost() URLScanner[S] {
+	return s.check(func(u *url.URL) error {
+		if u.Host == "" {
+			return fmt.Errorf("url %s: missing host", u.Redacted())
+		}
+
+		return nil
+	})
+}
+
+func (s URLScanner[S]) check(fn func(u *url.URL) error) URLScanner[S] {
+	convert := s.convert
+
+	s.convert = func(src S, cfg *config) (*url.URL, error) {
+		val, err := convert(src, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return val, fn(val)
+	}
+
+	return s
+}
+
+// String formats the URL, e.g. to store it normalized.
+func (s URLScanner[S]) String() StringScanner[S] {
+	return StringScanner[S]{
+		nullable: s.nullable,
+		err:      s.err,
+		convert: func(src S, cfg *config) (string, error) {
+			val, err := s.convert(src, cfg)
+			if err != nil || val == nil {
+				return "", err
+			}
+
+			return val.String(), nil
+		},
+	}
+}
+
+func (s URLScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s URLScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s URLScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var urlType = reflect.TypeFor[url.URL]()
+
+func (s URLScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv *url.URL) error, error) {
+	if dstType == urlType {
+		return func(dst reflect.Value, conv *url.URL) error {
+			if conv == nil {
+				dst.SetZero()
+
+				return nil
+			}
+
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*url.URL) = *conv
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to url.URL value", dstType)
+}
+
+type PrefixScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (netip.Prefix, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv netip.Prefix) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s PrefixScanner[S]) Nullable() PrefixScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s PrefixScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv netip.Prefix) error, error)) PrefixScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s PrefixScanner[S]) Or(alt PrefixScanner[S]) PrefixScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s PrefixScanner[S]) OnError(def netip.Prefix, report ...func(src S, err error)) PrefixScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func(netip.Prefix) (W, error).
+func (s PrefixScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s PrefixScanner[S]) stage() stage[S, netip.Prefix] {
+	return stage[S, netip.Prefix]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+// Masked zeroes the bits of the address outside the prefix, e.g. "10.1.2.3/8" to
+// "10.0.0.0/8", as net.ParseCIDR does.
+func (s PrefixScanner[S]) Masked() PrefixScanner[S] {
+	convert := s.convert
+
+	s.convert = func(src S, cfg *config) (netip.Prefix, error) {
+		val, err := convert(src, cfg)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+
+		return val.Masked(), nil
+	}
+
+	return s
+}
+
+func (s PrefixScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s PrefixScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s PrefixScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var (
+	prefixType = reflect.TypeFor[netip.Prefix]()
+	ipNetType  = reflect.TypeFor[net.IPNet]()
+	addrType   = reflect.TypeFor[netip.Addr]()
+	ipType     = reflect.TypeFor[net.IP]()
+)
+
+func (s PrefixScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv netip.Prefix) error, error) {
+	switch dstType {
+	case prefixType:
+		return func(dst reflect.Value, conv netip.Prefix) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*netip.Prefix) = conv
+
+			return nil
+		}, nil
+	case ipNetType:
+		return func(dst reflect.Value, conv netip.Prefix) error {
+			//nolint:forcetypeassert
+			*dst.Addr().Interface().(*net.IPNet) = net.IPNet{
+				IP:   conv.Addr().AsSlice(),
+				Mask: net.CIDRMask(conv.Bits(), conv.Addr().BitLen()),
+			}
+
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not assignable to netip.Prefix value", dstType)
+}
+
+type IPRangeScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([2]netip.Addr, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv [2]netip.Addr) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s IPRangeScanner[S]) Nullable() IPRangeScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s IPRangeScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv [2]netip.Addr) error, error)) IPRangeScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s IPRangeScanner[S]) Or(alt IPRangeScanner[S]) IPRangeScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s IPRangeScanner[S]) OnError(def [2]netip.Addr, report ...func(src S, err error)) IPRangeScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([2]netip.Addr) (W, error).
+func (s IPRangeScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s IPRangeScanner[S]) stage() stage[S, [2]netip.Addr] {
+	return stage[S, [2]netip.Addr]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s IPRangeScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s IPRangeScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s IPRangeScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+func (s IPRangeScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv [2]netip.Addr) error, error) {
+	if dstType.Kind() != reflect.Struct || dstType.NumField() != 2 {
+		return nil, fmt.Errorf("%s is not assignable to ip range value", dstType)
+	}
+
+	for i := range 2 {
+		if field := dstType.Field(i); !field.IsExported() || (field.Type != addrType && field.Type != ipType) {
+			return nil, fmt.Errorf("%s is not assignable to ip range value", dstType)
+		}
+	}
+
+	return func(dst reflect.Value, conv [2]netip.Addr) error {
+		for i, addr := range conv {
+			if field := dst.Field(i); field.Type() == addrType {
+				field.Set(reflect.ValueOf(addr))
+			} else {
+				field.Set(reflect.ValueOf(net.IP(addr.AsSlice())))
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+type JSONScanner[S any] struct {
+	nullable  nullMode
+	convert   func(src S, cfg *config) ([]byte, error)
+	err       error
+	strict    bool
+	useNumber bool
+	array     bool
+	merge     bool
+	decode    func(data []byte, v any) error
+	custom    func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s JSONScanner[S]) Nullable() JSONScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s JSONScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)) JSONScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s JSONScanner[S]) Or(alt JSONScanner[S]) JSONScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s JSONScanner[S]) OnError(def []byte, report ...func(src S, err error)) JSONScanner[S] {
+	s.convert = defaultConvert(s.convert, def, bytes.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]byte) (W, error).
+func (s JSONScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s JSONScanner[S]) stage() stage[S, []byte] {
+	return stage[S, []byte]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+// Path narrows the document to the fragment at path before it is unmarshalled, e.g.
+// "a.b[0].c". Missing keys and out of range indices yield null.
+func (s JSONScanner[S]) Path(path string) JSONScanner[S] {
+	segments, err := parseJSONPath(path)
+
+	convert := s.convert
+
+	s.err = errors.Join(s.err, err)
+	s.convert = func(src S, cfg *config) ([]byte, error) {
+		val, err := convert(src, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return extractJSON(val, segments)
+	}
+
+	return s
+}
+
+// Strict rejects objects containing keys that don't match a destination field.
+func (s JSONScanner[S]) Strict() JSONScanner[S] {
+	s.strict = true
+
+	return s
+}
+
+// UseNumber decodes numbers into interface values as json.Number instead of float64,
+// preserving large integer IDs.
+func (s JSONScanner[S]) UseNumber() JSONScanner[S] {
+	s.useNumber = true
+
+	return s
+}
+
+// Array requires a JSON array, rejecting null, objects and scalars, and a slice or
+// array destination, e.g. '[{"id":1},{"id":2}]' into a []Item field.
+func (s JSONScanner[S]) Array() JSONScanner[S] {
+	s.array = true
+
+	return s
+}
+
+// WithUnmarshal replaces encoding/json with fn, e.g. to use goccy/go-json or sonic.
+// It can't be combined with Strict or UseNumber, which configure encoding/json.
+func (s JSONScanner[S]) WithUnmarshal(fn func(data []byte, v any) error) JSONScanner[S] {
+	s.decode = fn
+
+	return s
+}
+
+// Merge lets the columns of several JSON scanners marked with Merge set the same
+// destination, e.g. a base and an override configuration. Each column decodes into
+// the value of the previous ones, so later columns override the fields and map
+// entries they contain and keep the others, and objects in map[string]any
+// destinations are merged recursively. Columns that are NULL, with Nullable, are
+// skipped.
+func (s JSONScanner[S]) Merge() JSONScanner[S] {
+	s.merge = true
+
+	return s
+}
+
+// MaxSize rejects documents larger than n bytes before any further processing, e.g.
+// to bound the memory of decoding user-supplied JSON columns.
+func (s JSONScanner[S]) MaxSize(n int) JSONScanner[S] {
+	if n < 0 {
+		s.err = errors.Join(s.err, fmt.Errorf("max size: invalid limit %d", n))
+	}
+
+	convert := s.convert
+
+	s.convert = func(src S, cfg *config) ([]byte, error) {
+		val, err := convert(src, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(val) > n {
+			return nil, fmt.Errorf("json document of %d bytes exceeds maximum size of %d bytes", len(val), n)
+		}
+
+		return val, nil
+	}
+
+	return s
+}
+
+func (s JSONScanner[S]) To(path string) Destination {
+	d := indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+	d.merge = s.merge
+
+	return d
+}
+
+func (s JSONScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s JSONScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var jsonMessageType = reflect.TypeFor[json.RawMessage]()
+
+func (s JSONScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
+	if dstType == jsonMessageType {
+		return func(dst reflect.Value, conv []byte) error {
+			dst.SetBytes(conv)
+
+			return nil
+		}, nil
+	}
+
+	if s.decode != nil && (s.strict || s.useNumber) {
+		return nil, errors.New("custom json unmarshal can't be combined with Strict or UseNumber")
+	}
+
+	unmarshal := s.unmarshal()
+
+	if s.array {
+		if kind := dstType.Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return nil, fmt.Errorf("%s is not a slice or array for json array", dstType)
+		}
+
+		return func(dst reflect.Value, conv []byte) error {
+			if trimmed := bytes.TrimLeft(conv, " \t\r\n"); len(trimmed) == 0 || trimmed[0] != '[' {
+				return fmt.Errorf("json value is not an array: %.20s", conv)
+			}
+
+			return unmarshal(conv, dst.Addr().Interface())
+		}, nil
+	}
+
+	if s.merge && dstType.Kind() == reflect.Map && dstType.Elem() == anyType {
+		return func(dst reflect.Value, conv []byte) error {
+			patch := reflect.New(dstType)
+
+			if err := unmarshal(conv, patch.Interface()); err != nil {
+				return err
+			}
+
+			if patch.Elem().IsNil() {
+				return nil
+			}
+
+			if dst.IsNil() {
+				dst.Set(patch.Elem())
+
+				return nil
+			}
+
+			mergeMaps(dst, patch.Elem())
+
+			return nil
+		}, nil
+	}
+
+	if dstType.Kind() == reflect.Map && dstType.Key().Kind() == reflect.String && dstType.Elem().Kind() != reflect.Interface {
+		return func(dst reflect.Value, conv []byte) error {
+			err := unmarshal(conv, dst.Addr().Interface())
+
+			if te := (*json.UnmarshalTypeError)(nil); errors.As(err, &te) {
+				return jsonKeyErr(conv, dstType.Elem(), unmarshal, err)
+			}
+
+			return err
+		}, nil
+	}
+
+	return func(dst reflect.Value, conv []byte) error {
+		return unmarshal(conv, dst.Addr().Interface())
+	}, nil
+}
+
+// jsonKeyError is an *json.UnmarshalTypeError of a map element, reported with the key
+// of the element.
+type jsonKeyError struct {
+	key string
+	err *json.UnmarshalTypeError
+}
+
+func (e jsonKeyError) Error() string {
+	return fmt.Sprintf("key '%s': cannot unmarshal %s into %s", e.key, e.err.Value, e.err.Type)
+}
+
+func (e jsonKeyError) Unwrap() error {
+	return e.err
+}
+
+// jsonKeyErr decodes the elements of the object data one by one into elemType to find
+// the key of the element failing with an *json.UnmarshalTypeError, returning err if
+// there is none.
+func jsonKeyErr(data []byte, elemType reflect.Type, unmarshal func(data []byte, v any) error, err error) error {
+	var elems map[string]json.RawMessage
+
+	if json.Unmarshal(data, &elems) != nil {
+		return err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(elems)) {
+		te := (*json.UnmarshalTypeError)(nil)
+
+		if !errors.As(unmarshal(elems[key], reflect.New(elemType).Interface()), &te) {
+			continue
+		}
+
+		if te.Field != "" {
+			key += "." + te.Field
+		}
+
+		return jsonKeyError{key: key, err: te}
+	}
+
+	return err
+}
+
+// mergeMaps sets the entries of patch in dst, both maps with interface values,
+// merging values that are map[string]any in both recursively.
+func mergeMaps(dst, patch reflect.Value) {
+	iter := patch.MapRange()
+
+	for iter.Next() {
+		if prev := dst.MapIndex(iter.Key()); prev.IsValid() {
+			prevMap, ok := prev.Interface().(map[string]any)
+			patchMap, patchOK := iter.Value().Interface().(map[string]any)
+
+			if ok && patchOK && prevMap != nil {
+				mergeMaps(reflect.ValueOf(prevMap), reflect.ValueOf(patchMap))
+
+				continue
+			}
+		}
+
+		dst.SetMapIndex(iter.Key(), iter.Value())
+	}
+}
+
+func (s JSONScanner[S]) unmarshal() func(data []byte, v any) error {
+	if s.decode != nil {
+		return s.decode
+	}
+
+	if !s.strict && !s.useNumber {
+		return json.Unmarshal
+	}
+
+	return func(data []byte, v any) error {
+		dec := json.NewDecoder(bytes.NewReader(data))
+
+		if s.strict {
+			dec.DisallowUnknownFields()
+		}
+
+		if s.useNumber {
+			dec.UseNumber()
+		}
+
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+
+		if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+			return errors.New("invalid data after top-level json value")
+		}
+
+		return nil
+	}
+}
+
+type jsonSegment struct {
+	key   string
+	index int
+}
+
+func parseJSONPath(path string) ([]jsonSegment, error) {
+	var segments []jsonSegment
+
+	for part := range strings.SplitSeq(path, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+
+		if key != "" {
+			segments = append(segments, jsonSegment{key: key, index: -1})
+		} else if rest == "" && !strings.HasPrefix(part, "[") {
+			return nil, fmt.Errorf("json path %s: empty key", path)
+		}
+
+		for rest != "" {
+			num, after, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, fmt.Errorf("json path %s: missing ]", path)
+			}
+
+			index, err := strconv.Atoi(num)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("json path %s: invalid index %s", path, num)
+			}
+
+			segments = append(segments, jsonSegment{index: index})
+
+			if after == "" {
+				break
+			}
+
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("json path %s: unexpected %s", path, after)
+			}
+
+			rest = after[1:]
+		}
+	}
+
+	return segments, nil
+}
+
+// extractJSON returns the value at segments in val, or a new null for a missing key or
+// index, as the result may be set into []byte destinations the caller modifies.
+func extractJSON(val []byte, segments []jsonSegment) ([]byte, error) {
+	for _, seg := range segments {
+		if seg.index < 0 {
+			var obj map[string]json.RawMessage
+
+			if err := json.Unmarshal(val, &obj); err != nil {
+				return nil, fmt.Errorf("json path key %s: %w", seg.key, err)
+			}
+
+			next, ok := obj[seg.key]
+			if !ok {
+				return []byte("null"), nil
+			}
+
+			val = next
+
+			continue
+		}
+
+		var arr []json.RawMessage
+
+		if err := json.Unmarshal(val, &arr); err != nil {
+			return nil, fmt.Errorf("json path index %d: %w", seg.index, err)
+		}
+
+		if seg.index >= len(arr) {
+			return []byte("null"), nil
+		}
+
+		val = arr[seg.index]
+	}
+
+	return val, nil
+}
+
+type TextScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([]byte, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s TextScanner[S]) Nullable() TextScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s TextScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)) TextScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s TextScanner[S]) Or(alt TextScanner[S]) TextScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s TextScanner[S]) OnError(def []byte, report ...func(src S, err error)) TextScanner[S] {
+	s.convert = defaultConvert(s.convert, def, bytes.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]byte) (W, error).
+func (s TextScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s TextScanner[S]) stage() stage[S, []byte] {
+	return stage[S, []byte]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s TextScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s TextScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
 }
 
-func (s StringScanner[S]) ParseInt(base int, bitSize int) IntScanner[S] {
-	return IntScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (int64, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return 0, err
-			}
+func (s TextScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
 
-			return strconv.ParseInt(val, base, bitSize)
-		},
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+func (s TextScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
+	if reflect.PointerTo(dstType).Implements(textUnmarshalerType) {
+		return func(dst reflect.Value, conv []byte) error {
+			//nolint:forcetypeassert
+			return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(conv)
+		}, nil
 	}
+
+	return nil, fmt.Errorf("%s doesn't implement encoding.TextUnmarshaler", dstType)
 }
 
-func (s StringScanner[S]) ParseUint(base int, bitSize int) UintScanner[S] {
-	return UintScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (uint64, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return 0, err
-			}
+type BinaryScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([]byte, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)
+}
 
-			return strconv.ParseUint(val, base, bitSize)
-		},
+// Nullable is like StringScanner.Nullable.
+func (s BinaryScanner[S]) Nullable() BinaryScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
 	}
+
+	return s
 }
 
-func (s StringScanner[S]) ParseFloat(bitSize int) FloatScanner[S] {
-	return FloatScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (float64, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return 0, err
-			}
+// WithSetter is like StringScanner.WithSetter.
+func (s BinaryScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)) BinaryScanner[S] {
+	s.custom = fn
 
-			return strconv.ParseFloat(val, bitSize)
-		},
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s BinaryScanner[S]) Or(alt BinaryScanner[S]) BinaryScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s BinaryScanner[S]) OnError(def []byte, report ...func(src S, err error)) BinaryScanner[S] {
+	s.convert = defaultConvert(s.convert, def, bytes.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]byte) (W, error).
+func (s BinaryScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s BinaryScanner[S]) stage() stage[S, []byte] {
+	return stage[S, []byte]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s BinaryScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s BinaryScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s BinaryScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+
+func (s BinaryScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
+	if reflect.PointerTo(dstType).Implements(binaryUnmarshalerType) {
+		return func(dst reflect.Value, conv []byte) error {
+			//nolint:forcetypeassert
+			return dst.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(conv)
+		}, nil
 	}
+
+	return nil, fmt.Errorf("%s doesn't implement encoding.BinaryUnmarshaler", dstType)
 }
 
-func (s StringScanner[S]) ParseBool() BoolScanner[S] {
-	return BoolScanner[S]{
+type GobScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) ([]byte, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s GobScanner[S]) Nullable() GobScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s GobScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)) GobScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s GobScanner[S]) Or(alt GobScanner[S]) GobScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s GobScanner[S]) OnError(def []byte, report ...func(src S, err error)) GobScanner[S] {
+	s.convert = defaultConvert(s.convert, def, bytes.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]byte) (W, error).
+func (s GobScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s GobScanner[S]) stage() stage[S, []byte] {
+	return stage[S, []byte]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s GobScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s GobScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s GobScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+func (s GobScanner[S]) setter(_ reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
+	return func(dst reflect.Value, conv []byte) error {
+		return gob.NewDecoder(bytes.NewReader(conv)).Decode(dst.Addr().Interface())
+	}, nil
+}
+
+// UnmarshalScanner decodes the column into the destination with an arbitrary
+// unmarshal function, e.g. msgpack.Unmarshal or cbor.Unmarshal.
+type UnmarshalScanner[S any] struct {
+	nullable  nullMode
+	convert   func(src S, cfg *config) ([]byte, error)
+	err       error
+	unmarshal func(data []byte, v any) error
+	custom    func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s UnmarshalScanner[S]) Nullable() UnmarshalScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s UnmarshalScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error)) UnmarshalScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s UnmarshalScanner[S]) Or(alt UnmarshalScanner[S]) UnmarshalScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s UnmarshalScanner[S]) OnError(def []byte, report ...func(src S, err error)) UnmarshalScanner[S] {
+	s.convert = defaultConvert(s.convert, def, bytes.Clone, report)
+
+	return s
+}
+
+// Convert is like StringScanner.Convert for a func([]byte) (W, error).
+func (s UnmarshalScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s UnmarshalScanner[S]) stage() stage[S, []byte] {
+	return stage[S, []byte]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s UnmarshalScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s UnmarshalScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s UnmarshalScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+func (s UnmarshalScanner[S]) setter(_ reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
+	if s.unmarshal == nil {
+		return nil, errors.New("unmarshal function is nil")
+	}
+
+	return func(dst reflect.Value, conv []byte) error {
+		return s.unmarshal(conv, dst.Addr().Interface())
+	}, nil
+}
+
+// DecimalScanner assigns the textual representation of a number to arbitrary-precision
+// destinations without depending on their packages. The destination pointer must have
+// a SetString(string) method returning an error, a bool (big.Rat, big.Float) or ending
+// with an error (apd.Decimal), or implement encoding.TextUnmarshaler (shopspring/decimal).
+type DecimalScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (string, error)
+	err      error
+	custom   func(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s DecimalScanner[S]) Nullable() DecimalScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s DecimalScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error)) DecimalScanner[S] {
+	s.custom = fn
+
+	return s
+}
+
+// Or is like StringScanner.Or.
+func (s DecimalScanner[S]) Or(alt DecimalScanner[S]) DecimalScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
+
+	return s
+}
+
+// OnError is like StringScanner.OnError.
+func (s DecimalScanner[S]) OnError(def string, report ...func(src S, err error)) DecimalScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
+
+	return s
+}
+
+// If is like StringScanner.If.
+func (s DecimalScanner[S]) If(pred func(v string) bool) IfScanner[S, string, DecimalScanner[string]] {
+	root := DefaultScanner{nullable: s.nullable}.Decimal()
+
+	return IfScanner[S, string, DecimalScanner[string]]{
 		nullable: s.nullable,
-		convert: func(src S) (bool, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return false, err
-			}
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
+	}
+}
 
-			return strconv.ParseBool(val)
+// Convert is like StringScanner.Convert for a func(string) (W, error).
+func (s DecimalScanner[S]) Convert(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "convert", fn)
+}
+
+func (s DecimalScanner[S]) stage() stage[S, string] {
+	return stage[S, string]{nullable: s.nullable, err: s.err, convert: s.convert}
+}
+
+func (s DecimalScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
+}
+
+func (s DecimalScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
+
+func (s DecimalScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+type decimalSetter interface {
+	SetString(s string) error
+}
+
+var (
+	decimalSetterType = reflect.TypeFor[decimalSetter]()
+	errorType         = reflect.TypeFor[error]()
+)
+
+func (s DecimalScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error) {
+	ptrType := reflect.PointerTo(dstType)
+
+	if ptrType.Implements(decimalSetterType) {
+		return func(dst reflect.Value, conv string) error {
+			//nolint:forcetypeassert
+			return dst.Addr().Interface().(decimalSetter).SetString(conv)
+		}, nil
+	}
+
+	if method, ok := ptrType.MethodByName("SetString"); ok &&
+		method.Type.NumIn() == 2 && method.Type.In(1) == stringType && method.Type.NumOut() > 0 {
+		switch method.Type.Out(method.Type.NumOut() - 1) {
+		case errorType:
+			return func(dst reflect.Value, conv string) error {
+				out := dst.Addr().Method(method.Index).Call([]reflect.Value{reflect.ValueOf(conv)})
+
+				if err := out[len(out)-1]; !err.IsNil() {
+					//nolint:forcetypeassert
+					return err.Interface().(error)
+				}
+
+				return nil
+			}, nil
+		case boolType:
+			return func(dst reflect.Value, conv string) error {
+				out := dst.Addr().Method(method.Index).Call([]reflect.Value{reflect.ValueOf(conv)})
+
+				if !out[len(out)-1].Bool() {
+					return fmt.Errorf("invalid decimal value %q for %s", conv, dstType)
+				}
+
+				return nil
+			}, nil
+		}
+	}
+
+	if ptrType.Implements(textUnmarshalerType) {
+		return func(dst reflect.Value, conv string) error {
+			//nolint:forcetypeassert
+			return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(conv))
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s doesn't implement SetString or encoding.TextUnmarshaler", dstType)
+}
+
+type ScanFunc func(typ reflect.Type) (any, func(dst reflect.Value) error, error)
+
+func (sf ScanFunc) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return sf(typ)
+}
+
+// Destination is a scanner setting the field at a path, as returned by To.
+type Destination struct {
+	path string
+	scan func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error)
+	// base is the scanner of a plain Scan().To(path), which AdaptTo may replace.
+	base   *DefaultScanner
+	expect *expectation
+	// merge allows other destinations with merge to set the same field.
+	merge bool
+}
+
+type expectation struct {
+	dbTypes   []string
+	scanTypes []reflect.Type
+}
+
+// ExpectDBType makes All, One and First fail with ErrColumnType if the column has none
+// of the database type names, e.g. ExpectDBType("TIMESTAMPTZ"), compared ignoring
+// case. It applies to rows that report column types, such as *sql.Rows.
+func (d Destination) ExpectDBType(names ...string) Destination {
+	expect := d.expectation()
+	expect.dbTypes = names
+	d.expect = &expect
+
+	return d
+}
+
+// ExpectScanType is like ExpectDBType for the types the driver reports to scan the
+// column into, e.g. ExpectScanType(reflect.TypeFor[time.Time]()) to surface drivers
+// returning timestamps as text.
+func (d Destination) ExpectScanType(types ...reflect.Type) Destination {
+	expect := d.expectation()
+	expect.scanTypes = types
+	d.expect = &expect
+
+	return d
+}
+
+func (d Destination) expectation() expectation {
+	if d.expect == nil {
+		return expectation{}
+	}
+
+	return *d.expect
+}
+
+func (e *expectation) check(col *sql.ColumnType) error {
+	if e.dbTypes != nil && !slices.ContainsFunc(e.dbTypes, func(name string) bool {
+		return strings.EqualFold(name, col.DatabaseTypeName())
+	}) {
+		return fmt.Errorf("%w: database type %q, expected one of %q", ErrColumnType, col.DatabaseTypeName(), e.dbTypes)
+	}
+
+	if e.scanTypes != nil && !slices.Contains(e.scanTypes, col.ScanType()) {
+		return fmt.Errorf("%w: scan type %v, expected one of %v", ErrColumnType, col.ScanType(), e.scanTypes)
+	}
+
+	return nil
+}
+
+func (d Destination) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return d.scan(typ, config{})
+}
+
+// As sets an interface-typed destination to a new value from factory per row, into
+// which the column is scanned as if it were the destination, e.g.
+//
+//	JSON().To("Payload").As(func() any { return &EmailPayload{} })
+func (d Destination) As(factory func() any) Destination {
+	return Destination{
+		path:   d.path,
+		expect: d.expect,
+		merge:  d.merge,
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			cfg.factory = factory
+
+			return d.scan(typ, cfg)
 		},
 	}
 }
 
-func (s StringScanner[S]) ParseTime(layout string) TimeScanner[S] {
-	return TimeScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (time.Time, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return time.Time{}, err
-			}
+type AnyScanner[S any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (any, error)
+	// typ is the result type of the last Case, if any.
+	typ    reflect.Type
+	err    error
+	custom func(dstType reflect.Type) (func(dst reflect.Value, conv any) error, error)
+}
+
+// Nullable is like StringScanner.Nullable.
+func (s AnyScanner[S]) Nullable() AnyScanner[S] {
+	if s.nullable == notNull {
+		s.nullable = nullSkip
+	}
+
+	return s
+}
+
+// WithSetter is like StringScanner.WithSetter.
+func (s AnyScanner[S]) WithSetter(fn func(dstType reflect.Type) (func(dst reflect.Value, conv any) error, error)) AnyScanner[S] {
+	s.custom = fn
 
-			return time.Parse(layout, val)
-		},
-	}
+	return s
 }
 
-func (s StringScanner[S]) ParseTimeInLocation(layout string, loc *time.Location) TimeScanner[S] {
-	return TimeScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (time.Time, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return time.Time{}, err
-			}
+// Or is like StringScanner.Or.
+func (s AnyScanner[S]) Or(alt AnyScanner[S]) AnyScanner[S] {
+	s.err = errors.Join(s.err, alt.err)
+	s.convert = orConvert(s.convert, alt.convert)
 
-			return time.ParseInLocation(layout, val, loc)
-		},
+	if s.typ != alt.typ {
+		s.typ = nil
 	}
+
+	return s
 }
 
-func (s StringScanner[S]) Trim(cutset string) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return "", err
-			}
+// OnError is like StringScanner.OnError. Unlike the typed scanners' OnError, it can't
+// copy def, so every failing value shares it.
+func (s AnyScanner[S]) OnError(def any, report ...func(src S, err error)) AnyScanner[S] {
+	s.convert = defaultConvert(s.convert, def, nil, report)
 
-			return strings.Trim(val, cutset), nil
-		},
-	}
+	return s
 }
 
-func (s StringScanner[S]) TrimSpace() StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return "", err
-			}
+// If is like StringScanner.If.
+func (s AnyScanner[S]) If(pred func(v any) bool) IfScanner[S, any, AnyScanner[any]] {
+	root := DefaultScanner{nullable: s.nullable}.Any()
 
-			return strings.TrimSpace(val), nil
-		},
+	return IfScanner[S, any, AnyScanner[any]]{
+		nullable: s.nullable,
+		convert:  s.convert,
+		err:      s.err,
+		pred:     pred,
+		root:     root,
+		then:     root,
+		els:      root,
 	}
 }
 
-func (s StringScanner[S]) TrimPrefix(prefix string) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return "", err
-			}
+// Case converts the value with fn, a func(v any) (V, error), typically switching on
+// the type of v. To then requires a destination V is assignable to.
+func (s AnyScanner[S]) Case(fn any) AnyScanner[S] {
+	return convertScanner(s.nullable, s.err, s.convert, "case", fn)
+}
 
-			return strings.TrimPrefix(val, prefix), nil
-		},
-	}
+func (s AnyScanner[S]) To(path string) Destination {
+	return indirectScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, path)
 }
 
-func (s StringScanner[S]) TrimSuffix(suffix string) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return "", err
-			}
+func (s AnyScanner[S]) ToFunc(fn any) Scanner {
+	return funcScanFunc(s.nullable, s.err, customSetter(s.custom, s.setter), s.convert, fn)
+}
 
-			return strings.TrimSuffix(val, suffix), nil
-		},
+func (s AnyScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+var anyType = reflect.TypeFor[any]()
+
+func (s AnyScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv any) error, error) {
+	if s.typ != nil && s.typ.Kind() != reflect.Interface && !anyAssignable(s.typ, dstType) {
+		return nil, fmt.Errorf("%s is not assignable to %s value", dstType, s.typ)
 	}
+
+	return func(dst reflect.Value, conv any) error {
+		if conv == nil {
+			dst.SetZero()
+
+			return nil
+		}
+
+		val := reflect.ValueOf(conv)
+
+		switch {
+		case val.Type().AssignableTo(dstType):
+			dst.Set(val)
+		case anyAssignable(val.Type(), dstType):
+			dst.Set(val.Convert(dstType))
+		default:
+			return fmt.Errorf("%s is not assignable to %T value", dstType, conv)
+		}
+
+		return nil
+	}, nil
 }
 
-type Enum struct {
-	String string
-	Int    int64
+// convertScanner returns an AnyScanner converting the values of convert with fn, a
+// func(V) (W, error), for the Convert and Case methods named by name.
+// Stage is implemented by the scanners converting sources of S into values of V, such
+// as StringScanner[S] with V string, see ConvertTo.
+type Stage[S, V any] interface {
+	stage() stage[S, V]
 }
 
-func (s StringScanner[S]) Enum(enums ...Enum) IntScanner[S] {
-	return IntScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (int64, error) {
-			conv, err := s.convert(src)
+type stage[S, V any] struct {
+	nullable nullMode
+	err      error
+	convert  func(src S, cfg *config) (V, error)
+}
+
+// ConvertTo is like the Convert method of s, but typed, so that fn not accepting the
+// value of s fails to compile, e.g.
+//
+//	structscan.ConvertTo(structscan.String().TrimSpace(), time.ParseDuration).To("Timeout")
+func ConvertTo[S, V, W any](s Stage[S, V], fn func(v V) (W, error)) AnyScanner[S] {
+	st := s.stage()
+
+	return AnyScanner[S]{
+		nullable: st.nullable,
+		err:      st.err,
+		typ:      reflect.TypeFor[W](),
+		convert: func(src S, cfg *config) (any, error) {
+			val, err := st.convert(src, cfg)
 			if err != nil {
-				return 0, err
+				return nil, err
 			}
 
-			for _, each := range enums {
-				if each.String == conv {
-					return each.Int, nil
-				}
+			conv, err := fn(val)
+			if err != nil {
+				return nil, err
 			}
 
-			return 0, fmt.Errorf("value %s is not one of enums: %v", conv, enums)
+			return conv, nil
 		},
 	}
 }
 
-func (s StringScanner[S]) Split(sep string) StringSliceScanner[S] {
-	return StringSliceScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) ([]string, error) {
-			val, err := s.convert(src)
+func convertScanner[S, V any](nullable nullMode, err error, convert func(src S, cfg *config) (V, error), name string, fn any) AnyScanner[S] {
+	var (
+		fv      = reflect.ValueOf(fn)
+		valType = reflect.TypeFor[V]()
+	)
+
+	if fv.Kind() != reflect.Func || fv.Type().NumIn() != 1 || fv.Type().In(0) != valType ||
+		fv.Type().NumOut() != 2 || fv.Type().Out(1) != errorType {
+		return AnyScanner[S]{
+			nullable: nullable,
+			err:      errors.Join(err, fmt.Errorf("%s: expected func(%s) (W, error), got %T", name, valType, fn)),
+		}
+	}
+
+	return AnyScanner[S]{
+		nullable: nullable,
+		err:      err,
+		typ:      fv.Type().Out(0),
+		convert: func(src S, cfg *config) (any, error) {
+			val, err := convert(src, cfg)
 			if err != nil {
 				return nil, err
 			}
 
-			if val == "" {
-				return []string{}, nil
+			out := fv.Call([]reflect.Value{reflect.ValueOf(&val).Elem()})
+
+			if err, _ := out[1].Interface().(error); err != nil {
+				return nil, err
 			}
 
-			return strings.Split(val, sep), nil
+			return out[0].Interface(), nil
 		},
 	}
 }
 
-func (s StringScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
+// IfScanner routes values of type V between two chains starting with scanners of type B.
+type IfScanner[S, V, B any] struct {
+	nullable nullMode
+	convert  func(src S, cfg *config) (V, error)
+	err      error
+	pred     func(v V) bool
+	root     B
+	then     Scanner
+	els      Scanner
 }
 
-func (s StringScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
+// Then sets the chain for values matching the predicate of If to the one fn builds
+// from a scanner of the value, e.g. func(s StringScanner[string]) Scanner {
+// return s.ParseInt(10, 64) }.
+func (s IfScanner[S, V, B]) Then(fn func(s B) Scanner) IfScanner[S, V, B] {
+	s.then = fn(s.root)
+
+	return s
 }
 
-var stringType = reflect.TypeFor[string]()
+// Else is like Then for the other values.
+func (s IfScanner[S, V, B]) Else(fn func(s B) Scanner) IfScanner[S, V, B] {
+	s.els = fn(s.root)
 
-func (s StringScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error) {
-	if dstType == stringType {
-		return func(dst reflect.Value, conv string) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*string) = conv
+	return s
+}
 
-			return nil
-		}, nil
-	}
+func (s IfScanner[S, V, B]) To(path string) Destination {
+	then, els := branchTo(s.then, path), branchTo(s.els, path)
 
-	if dstType.Kind() == reflect.String {
-		return func(dst reflect.Value, conv string) error {
-			dst.SetString(conv)
+	return ifDestination(s.nullable, s.err, s.convert, s.pred, then, els, path)
+}
 
-			return nil
-		}, nil
+func (s IfScanner[S, V, B]) ToFunc(fn any) Scanner {
+	then, els := branchToFunc(s.then, fn), branchToFunc(s.els, fn)
+
+	return ifDestination(s.nullable, s.err, s.convert, s.pred, then, els, "")
+}
+
+func (s IfScanner[S, V, B]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
+	return s.To("").Scan(typ)
+}
+
+func branchTo(s Scanner, path string) Scanner {
+	if to, ok := s.(interface{ To(path string) Destination }); ok {
+		return to.To(path)
 	}
 
-	return nil, fmt.Errorf("%s is not assignable to string value", dstType)
+	return errorDestination(path, fmt.Errorf("if: %T has no To method", s))
 }
 
-type IntScanner[S any] struct {
-	nullable bool
-	convert  func(src S) (int64, error)
+func branchToFunc(s Scanner, fn any) Scanner {
+	if to, ok := s.(interface{ ToFunc(fn any) Scanner }); ok {
+		return to.ToFunc(fn)
+	}
+
+	return errorDestination("", fmt.Errorf("if: %T has no ToFunc method", s))
 }
 
-func (s IntScanner[S]) Format(base int) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
+func ifDestination[S, V any](nullable nullMode, err error, convert func(src S, cfg *config) (V, error), pred func(v V) bool, then, els Scanner, path string) Destination {
+	if err != nil {
+		return errorDestination(path, err)
+	}
+
+	return Destination{
+		path: path,
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			thenSrc, thenSet, err := scanConfig(then, typ, cfg)
 			if err != nil {
-				return "", err
+				return nil, nil, err
 			}
 
-			return strconv.FormatInt(val, base), nil
-		},
-	}
-}
+			elseSrc, elseSet, err := scanConfig(els, typ, cfg)
+			if err != nil {
+				return nil, nil, err
+			}
 
-func (s IntScanner[S]) Enum(enums ...Enum) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			conv, err := s.convert(src)
+			thenPut, err := branchSource[V](thenSrc)
 			if err != nil {
-				return "", err
+				return nil, nil, err
 			}
 
-			for _, each := range enums {
-				if each.Int == conv {
-					return each.String, nil
+			elsePut, err := branchSource[V](elseSrc)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			convert := trimmed(convert, cfg.trim)
+
+			decode := func(dst reflect.Value, src S) error {
+				val, err := convert(src, &cfg)
+				if err != nil {
+					return conversion(err)
+				}
+
+				if pred(val) {
+					thenPut(val, true)
+
+					return thenSet(dst)
 				}
+
+				elsePut(val, true)
+
+				return elseSet(dst)
 			}
 
-			return "", fmt.Errorf("value %d is not one of enums: %v", conv, enums)
+			if cfg.nullMode(nullable) != notNull {
+				var src sql.Null[S]
+
+				return &src, func(dst reflect.Value) error {
+					if !src.Valid {
+						var zero V
+
+						if !elsePut(zero, false) {
+							return nil
+						}
+
+						return elseSet(dst)
+					}
+
+					return decode(dst, src.V)
+				}, nil
+			}
+
+			var src S
+
+			return &src, func(dst reflect.Value) error {
+				return decode(dst, src)
+			}, nil
 		},
 	}
 }
 
-func (s IntScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
+// branchSource returns a function putting values into src, the source of a branch of
+// an IfScanner, and reporting whether the branch is to be set.
+func branchSource[V any](src any) (func(val V, valid bool) bool, error) {
+	switch src := src.(type) {
+	case *V:
+		return func(val V, valid bool) bool {
+			*src = val
 
-func (s IntScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
+			return valid
+		}, nil
+	case *sql.Null[V]:
+		return func(val V, valid bool) bool {
+			src.V, src.Valid = val, valid
+
+			return true
+		}, nil
+	default:
+		return nil, fmt.Errorf("if: branch scans %T, expected %s", src, reflect.TypeFor[V]())
+	}
 }
 
-var intType = reflect.TypeFor[int64]()
+// orConvert returns convert falling back to alt on errors.
+func orConvert[S, C any](convert, alt func(src S, cfg *config) (C, error)) func(src S, cfg *config) (C, error) {
+	return func(src S, cfg *config) (C, error) {
+		conv, err := convert(src, cfg)
+		if err == nil {
+			return conv, nil
+		}
 
-func (s IntScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv int64) error, error) {
-	if dstType == intType {
-		return func(dst reflect.Value, conv int64) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*int64) = conv
+		conv, altErr := alt(src, cfg)
+		if altErr != nil {
+			return conv, errors.Join(err, altErr)
+		}
 
-			return nil
-		}, nil
+		return conv, nil
 	}
+}
 
-	//nolint:exhaustive
-	switch dstType.Kind() {
-	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
-		return func(dst reflect.Value, conv int64) error {
-			if dst.OverflowInt(conv) {
-				return fmt.Errorf("overflow of int64 value %d to %s", conv, dstType)
+// defaultConvert returns convert converting to def on errors, which it reports. Unless
+// clone is nil, each error gets a clone of def, so later steps modifying values in place
+// don't change def or the values of other rows.
+func defaultConvert[S, C any](convert func(src S, cfg *config) (C, error), def C, clone func(C) C, report []func(src S, err error)) func(src S, cfg *config) (C, error) {
+	return func(src S, cfg *config) (C, error) {
+		conv, err := convert(src, cfg)
+		if err != nil {
+			for _, fn := range report {
+				fn(src, err)
 			}
 
-			dst.SetInt(conv)
-
-			return nil
-		}, nil
-	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
-		return func(dst reflect.Value, conv int64) error {
-			if conv < 0 {
-				return fmt.Errorf("lossy conversion of int64 value %d to %s", conv, dstType)
+			if clone != nil {
+				return clone(def), nil
 			}
 
-			v := uint64(conv)
+			return def, nil
+		}
 
-			if dst.OverflowUint(v) {
-				return fmt.Errorf("overflow of int64 value %d to %s", conv, dstType)
-			}
+		return conv, nil
+	}
+}
 
-			dst.SetUint(v)
+// cloneValues returns a deep copy of v.
+func cloneValues(v url.Values) url.Values {
+	if v == nil {
+		return nil
+	}
 
-			return nil
-		}, nil
-	case reflect.Float64, reflect.Float32:
-		return func(dst reflect.Value, conv int64) error {
-			v := float64(conv)
+	clone := make(url.Values, len(v))
 
-			if dst.OverflowFloat(v) {
-				return fmt.Errorf("overflow of int64 value %d to %s", conv, dstType)
-			}
+	for key, vals := range v {
+		clone[key] = slices.Clone(vals)
+	}
 
-			dst.SetFloat(v)
+	return clone
+}
 
-			return nil
-		}, nil
+// cloneURL returns a copy of u.
+func cloneURL(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
 	}
 
-	return nil, fmt.Errorf("%s is not assignable to int64 value", dstType)
-}
+	clone := *u
 
-type UintScanner[S any] struct {
-	nullable bool
-	convert  func(src S) (uint64, error)
+	if u.User != nil {
+		user := *u.User
+		clone.User = &user
+	}
+
+	return &clone
 }
 
-func (s UintScanner[S]) Format(base int) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return "", err
-			}
+// customSetter returns setter preceded by custom, if not nil.
+func customSetter[C any](
+	custom func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error),
+	setter func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error),
+) func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error) {
+	if custom == nil {
+		return setter
+	}
 
-			return strconv.FormatUint(val, base), nil
-		},
+	return func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error) {
+		set, err := custom(dstType)
+		if err == nil {
+			return set, nil
+		}
+
+		set, setterErr := setter(dstType)
+		if setterErr != nil {
+			return nil, errors.Join(err, setterErr)
+		}
+
+		return set, nil
 	}
 }
 
-func (s UintScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
+// anyAssignable reports whether values of src can be set to dst directly or by
+// converting between types of the same kind, such as string and MyString.
+func anyAssignable(src, dst reflect.Type) bool {
+	return src.AssignableTo(dst) || (src.Kind() == dst.Kind() && src.ConvertibleTo(dst))
 }
 
-func (s UintScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
+func errorDestination(path string, err error) Destination {
+	return Destination{
+		path: path,
+		scan: func(reflect.Type, config) (any, func(dst reflect.Value) error, error) {
+			return nil, nil, err
+		},
+	}
 }
 
-var uint64Type = reflect.TypeFor[uint64]()
+func scanConfig(s Scanner, typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+	if to, ok := s.(interface{ To(path string) Destination }); ok {
+		s = to.To("")
+	}
 
-func (s UintScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv uint64) error, error) {
-	if dstType == uint64Type {
-		return func(dst reflect.Value, conv uint64) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*uint64) = conv
+	if d, ok := s.(Destination); ok {
+		return d.scan(typ, cfg)
+	}
 
-			return nil
-		}, nil
+	return s.Scan(typ)
+}
+
+func indirectScanFunc[S, C any](
+	nullable nullMode,
+	err error,
+	setter func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error),
+	convert func(src S, cfg *config) (C, error),
+	path string,
+) Destination {
+	if err != nil {
+		return errorDestination(path, err)
 	}
 
-	//nolint:exhaustive
-	switch dstType.Kind() {
-	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
-		return func(dst reflect.Value, conv uint64) error {
-			if dst.OverflowUint(conv) {
-				return fmt.Errorf("overflow of uint64 value %d to %s", conv, dstType)
+	return Destination{
+		path: path,
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			indices, key, dstType, err := destAccessor(typ, cfg.resolve(typ, path), cfg.depth())
+			if err != nil {
+				return nil, nil, err
 			}
 
-			dst.SetUint(conv)
+			convert := trimmed(convert, cfg.trim)
+			nullable := cfg.nullMode(nullable)
 
-			return nil
-		}, nil
-	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
-		return func(dst reflect.Value, conv uint64) error {
-			if conv > math.MaxInt64 {
-				return fmt.Errorf("lossy conversion of uint64 value %d to %s", conv, dstType)
+			if err = nullCheck(nullable, typ, indices, key); err != nil {
+				return nil, nil, categoryError{ErrNotAssignable, fmt.Errorf("path %s: %w", path, err)}
 			}
 
-			v := int64(conv)
+			set, err := destSetter(dstType, cfg.factory, setter)
+			if err != nil {
+				if path != "" {
+					err = fmt.Errorf("path %s: %w", path, err)
+				}
 
-			if dst.OverflowInt(v) {
-				return fmt.Errorf("overflow of uint64 value %d to %s", conv, dstType)
+				return nil, nil, categoryError{ErrNotAssignable, err}
 			}
 
-			dst.SetInt(v)
+			at := compileAccess(typ, indices)
 
-			return nil
-		}, nil
-	case reflect.Float64, reflect.Float32:
-		return func(dst reflect.Value, conv uint64) error {
-			v := float64(conv)
+			if nullable != notNull {
+				var (
+					src   sql.Null[S]
+					reset = nullReset(nullable, typ, indices, key, cfg.prune)
+				)
 
-			if dst.OverflowFloat(v) {
-				return fmt.Errorf("overflow of uint64 value %d to %s", conv, dstType)
+				return &src, func(dst reflect.Value) error {
+					if !src.Valid {
+						reset(dst)
+
+						return nil
+					}
+
+					conv, err := convert(src.V, &cfg)
+					if err != nil {
+						return conversion(err)
+					}
+
+					return conversion(assign(dst, at, key, set, conv))
+				}, nil
 			}
 
-			dst.SetFloat(v)
+			var src S
 
-			return nil
-		}, nil
-	}
+			return &src, func(dst reflect.Value) error {
+				conv, err := convert(src, &cfg)
+				if err != nil {
+					return conversion(err)
+				}
 
-	return nil, fmt.Errorf("%s is not assignable to uint64 value", dstType)
+				return conversion(assign(dst, at, key, set, conv))
+			}, nil
+		},
+	}
 }
 
-type FloatScanner[S any] struct {
-	nullable bool
-	convert  func(src S) (float64, error)
-}
+func funcScanFunc[S, C any](
+	nullable nullMode,
+	err error,
+	setter func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error),
+	convert func(src S, cfg *config) (C, error),
+	fn any,
+) Destination {
+	if err != nil {
+		return errorDestination("", err)
+	}
 
-func (s FloatScanner[S]) Format(fmt byte, prec int, bitSize int) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
+	return Destination{
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			call, valType, err := funcDestination(typ, fn)
 			if err != nil {
-				return "", err
+				return nil, nil, err
 			}
 
-			return strconv.FormatFloat(val, fmt, prec, bitSize), nil
-		},
-	}
-}
+			set, err := fieldSetter(derefType(valType), setter)
+			if err != nil {
+				return nil, nil, categoryError{ErrNotAssignable, err}
+			}
 
-func (s FloatScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
+			convert := trimmed(convert, cfg.trim)
+			nullable := cfg.nullMode(nullable)
 
-func (s FloatScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
+			if nullable == nullNil {
+				if err = nilable(valType); err != nil {
+					return nil, nil, categoryError{ErrNotAssignable, err}
+				}
+			}
 
-var float64Type = reflect.TypeFor[float64]()
+			decode := func(dst reflect.Value, src S) error {
+				conv, err := convert(src, &cfg)
+				if err != nil {
+					return conversion(err)
+				}
 
-func (s FloatScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv float64) error, error) {
-	if dstType == float64Type {
-		return func(dst reflect.Value, conv float64) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*float64) = conv
+				val := reflect.New(valType).Elem()
 
-			return nil
-		}, nil
-	}
+				if err = set(deref(val), conv); err != nil {
+					return conversion(err)
+				}
 
-	//nolint:exhaustive
-	switch dstType.Kind() {
-	case reflect.Float64, reflect.Float32:
-		return func(dst reflect.Value, conv float64) error {
-			dst.SetFloat(conv)
+				return call(dst, val)
+			}
 
-			return nil
-		}, nil
-	}
+			if nullable != notNull {
+				var src sql.Null[S]
 
-	return nil, fmt.Errorf("%s is not assignable to float64 value", dstType)
-}
+				return &src, func(dst reflect.Value) error {
+					if !src.Valid {
+						if nullable == nullNil || nullable == nullZero {
+							return call(dst, reflect.Zero(valType))
+						}
 
-type BoolScanner[S any] struct {
-	nullable bool
-	convert  func(src S) (bool, error)
-}
+						return nil
+					}
 
-func (s BoolScanner[S]) Format() StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return "", err
+					return decode(dst, src.V)
+				}, nil
 			}
 
-			return strconv.FormatBool(val), nil
+			var src S
+
+			return &src, func(dst reflect.Value) error {
+				return decode(dst, src)
+			}, nil
 		},
 	}
 }
 
-func (s BoolScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
-
-func (s BoolScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
-
-var boolType = reflect.TypeFor[bool]()
-
-func (s BoolScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv bool) error, error) {
-	if dstType == boolType {
-		return func(dst reflect.Value, conv bool) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*bool) = conv
+// funcDestination checks that fn is a func(dst *T, v V) error for the destination
+// type typ and returns a function calling it along with V.
+func funcDestination(typ reflect.Type, fn any) (func(dst, val reflect.Value) error, reflect.Type, error) {
+	fv := reflect.ValueOf(fn)
 
-			return nil
-		}, nil
+	if fv.Kind() != reflect.Func || fv.Type().NumIn() != 2 || fv.Type().NumOut() != 1 ||
+		fv.Type().In(0) != reflect.PointerTo(derefType(typ)) || fv.Type().Out(0) != errorType {
+		return nil, nil, fmt.Errorf("func: expected func(*%s, V) error, got %T", derefType(typ), fn)
 	}
 
-	if dstType.Kind() == reflect.Bool {
-		return func(dst reflect.Value, conv bool) error {
-			dst.SetBool(conv)
+	return func(dst, val reflect.Value) error {
+		out := fv.Call([]reflect.Value{dst.Addr(), val})
 
-			return nil
-		}, nil
-	}
+		if err, _ := out[0].Interface().(error); err != nil {
+			return err
+		}
 
-	return nil, fmt.Errorf("%s is not assignable to bool value", dstType)
+		return nil
+	}, fv.Type().In(1), nil
 }
 
-type TimeScanner[S any] struct {
-	nullable bool
-	convert  func(src S) (time.Time, error)
+type accessorKey struct {
+	typ      reflect.Type
+	path     string
+	maxDepth int
 }
 
-func (s TimeScanner[S]) Format(layout string) StringScanner[S] {
-	return StringScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) (string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return "", err
-			}
-
-			return val.Format(layout), nil
-		},
-	}
+type accessorResult struct {
+	indices []int
+	typ     reflect.Type
 }
 
-func (s TimeScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
+// accessors caches the field indices and destination types that accessor resolves, so
+// schemas and runners over the same types don't repeat the walks. Setters depend on
+// the scanner and are built per schema. Failed lookups aren't cached. Entries are never
+// evicted, so the cache grows with every type scanned, including each distinct struct
+// type NewDynamic builds.
+var accessors sync.Map
 
-func (s TimeScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
+func accessor(typ reflect.Type, path string, maxDepth int) ([]int, reflect.Type, error) {
+	key := accessorKey{typ: typ, path: path, maxDepth: maxDepth}
 
-var timeType = reflect.TypeFor[time.Time]()
+	if cached, ok := accessors.Load(key); ok {
+		//nolint:forcetypeassert
+		res := cached.(accessorResult)
 
-func (s TimeScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv time.Time) error, error) {
-	if dstType == timeType {
-		return func(dst reflect.Value, conv time.Time) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*time.Time) = conv
+		return res.indices, res.typ, nil
+	}
 
-			return nil
-		}, nil
+	indices, dstType, err := walkAccessor(typ, path, maxDepth)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if timeType.ConvertibleTo(dstType) {
-		return func(dst reflect.Value, conv time.Time) error {
-			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+	// clipped, so that appending to the indices doesn't write into the cache
+	indices = slices.Clip(indices)
 
-			return nil
-		}, nil
-	}
+	accessors.Store(key, accessorResult{indices: indices, typ: dstType})
 
-	return nil, fmt.Errorf("%s is not assignable to time.Time value", dstType)
+	return indices, dstType, nil
 }
 
-type BytesScanner[S any] struct {
-	nullable bool
-	convert  func(src S) ([]byte, error)
-}
+func walkAccessor(typ reflect.Type, path string, maxDepth int) ([]int, reflect.Type, error) {
+	if path == "" {
+		return nil, derefType(typ), nil
+	}
 
-func (s BytesScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
+	var (
+		indices   []int
+		depth     int
+		recursive reflect.Type
+		visited   = map[reflect.Type]bool{derefType(typ): true}
+	)
 
-func (s BytesScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
+	for p := range strings.SplitSeq(path, ".") {
+		if derefType(typ).Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("path %s: %w", path, ErrPathNotFound)
+		}
 
-var bytesType = reflect.TypeFor[[]byte]()
+		sf, ok := derefType(typ).FieldByName(p)
+		if !ok {
+			if candidates := ambiguousFields(derefType(typ), p); len(candidates) > 0 {
+				return nil, nil, categoryError{ErrPathNotFound,
+					fmt.Errorf("path %s: ambiguous field %s, use one of %s", path, p, strings.Join(candidates, ", "))}
+			}
 
-func (s BytesScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
-	if dstType == bytesType {
-		return func(dst reflect.Value, conv []byte) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*[]byte) = conv
+			return nil, nil, fmt.Errorf("path %s: %w", path, ErrPathNotFound)
+		}
 
-			return nil
-		}, nil
-	}
+		if !sf.IsExported() {
+			return nil, nil, categoryError{ErrNotAssignable, fmt.Errorf("path %s: not exported", path)}
+		}
 
-	if dstType == jsonMessageType {
-		return func(dst reflect.Value, conv []byte) error {
-			dst.SetBytes(conv)
+		// Fields promoted from embedded structs are reached through each embedded field.
+		for t, i := derefType(typ), 0; i < len(sf.Index)-1; i++ {
+			embedded := t.Field(sf.Index[i])
 
-			return nil
-		}, nil
-	}
+			if embedded.Type.Kind() == reflect.Pointer {
+				if !embedded.IsExported() {
+					return nil, nil, fmt.Errorf("path %s: promoted through unexported embedded pointer %s", path, embedded.Type)
+				}
 
-	if bytesType.ConvertibleTo(dstType) {
-		return func(dst reflect.Value, conv []byte) error {
-			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+				depth++
+			}
 
-			return nil
-		}, nil
-	}
+			t = derefType(embedded.Type)
+		}
 
-	return nil, fmt.Errorf("%s is not assignable to []byte value", dstType)
-}
+		typ = sf.Type
 
-type StringSliceScanner[S any] struct {
-	nullable bool
-	convert  func(src S) ([]string, error)
-}
+		for t := typ; t.Kind() == reflect.Pointer; t = t.Elem() {
+			depth++
+		}
 
-func (s StringSliceScanner[S]) Asc() StringSliceScanner[S] {
-	return StringSliceScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) ([]string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return nil, err
+		if elem := derefType(typ); elem.Kind() == reflect.Struct {
+			if visited[elem] && recursive == nil {
+				recursive = elem
 			}
 
-			slices.Sort(val)
-
-			return val, nil
-		},
-	}
-}
+			visited[elem] = true
+		}
 
-func (s StringSliceScanner[S]) Desc() StringSliceScanner[S] {
-	return StringSliceScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) ([]string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return nil, err
+		if maxDepth >= 0 && depth > maxDepth {
+			if recursive != nil {
+				return nil, nil, fmt.Errorf("path %s: exceeds maximum depth of %d pointer allocations through recursive type %s",
+					path, maxDepth, recursive)
 			}
 
-			slices.Sort(val)
-			slices.Reverse(val)
+			return nil, nil, fmt.Errorf("path %s: exceeds maximum depth of %d pointer allocations", path, maxDepth)
+		}
 
-			return val, nil
-		},
+		indices = append(indices, sf.Index...)
 	}
+
+	return indices, derefType(typ), nil
 }
 
-func (s StringSliceScanner[S]) ParseInt(base int, bitSize int) IntSliceScanner[S] {
-	return IntSliceScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) ([]int64, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return nil, err
-			}
+// ambiguousFields returns the paths of the fields named name at the shallowest embedding
+// depth of typ if there is more than one, which is why FieldByName didn't find it.
+func ambiguousFields(typ reflect.Type, name string) []string {
+	type embedded struct {
+		typ    reflect.Type
+		prefix string
+	}
 
-			conv := make([]int64, len(val))
+	var (
+		level   = []embedded{{typ: typ}}
+		visited = map[reflect.Type]bool{typ: true}
+	)
 
-			for i, v := range val {
-				c, err := strconv.ParseInt(v, base, bitSize)
-				if err != nil {
-					return nil, err
+	for len(level) > 0 {
+		var (
+			matches []string
+			next    []embedded
+		)
+
+		for _, e := range level {
+			for i := range e.typ.NumField() {
+				field := e.typ.Field(i)
+
+				if field.Name == name {
+					matches = append(matches, e.prefix+field.Name)
 				}
 
-				conv[i] = c
+				if elem := derefType(field.Type); field.Anonymous && elem.Kind() == reflect.Struct && !visited[elem] {
+					next = append(next, embedded{typ: elem, prefix: e.prefix + field.Name + "."})
+				}
+			}
+		}
+
+		if len(matches) > 0 {
+			if len(matches) == 1 {
+				return nil
 			}
 
-			return conv, nil
-		},
+			return matches
+		}
+
+		for _, e := range next {
+			visited[e.typ] = true
+		}
+
+		level = next
 	}
-}
 
-func (s StringSliceScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
+	return nil
 }
 
-func (s StringSliceScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
+// destAccessor resolves path like accessor, except that a last segment naming a key
+// of a map with string keys, e.g. "Attrs.color", is returned as key.
+func destAccessor(typ reflect.Type, path string, maxDepth int) ([]int, reflect.Value, reflect.Type, error) {
+	indices, dstType, err := accessor(typ, path, maxDepth)
+	if err == nil {
+		return indices, reflect.Value{}, dstType, nil
+	}
 
-var stringSliceType = reflect.TypeFor[[]string]()
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return nil, reflect.Value{}, nil, err
+	}
 
-func (s StringSliceScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []string) error, error) {
-	if dstType == stringSliceType {
-		return func(dst reflect.Value, conv []string) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*[]string) = conv
+	indices, mapType, mapErr := accessor(typ, path[:i], maxDepth)
+	if mapErr != nil || mapType.Kind() != reflect.Map {
+		return nil, reflect.Value{}, nil, err
+	}
 
-			return nil
-		}, nil
+	if mapType.Key().Kind() != reflect.String {
+		return nil, reflect.Value{}, nil, fmt.Errorf("path %s: map key type %s is not a string", path, mapType.Key())
 	}
 
-	if stringSliceType.ConvertibleTo(dstType) {
-		return func(dst reflect.Value, conv []string) error {
-			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+	return indices, reflect.ValueOf(path[i+1:]).Convert(mapType.Key()), derefType(mapType.Elem()), nil
+}
 
-			return nil
-		}, nil
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
 	}
 
-	if dstType.Kind() == reflect.Slice && derefType(dstType.Elem()).Kind() == reflect.String {
-		return func(dst reflect.Value, conv []string) error {
-			dst.Set(reflect.MakeSlice(dstType, len(conv), len(conv)))
+	return t
+}
 
-			for i, v := range conv {
-				deref(dst.Index(i)).SetString(v)
-			}
+func deref(dst reflect.Value) reflect.Value {
+	for dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
 
-			return nil
-		}, nil
+		dst = dst.Elem()
 	}
 
-	return nil, fmt.Errorf("%s is not assignable to []string value", dstType)
+	return dst
 }
 
-type IntSliceScanner[S any] struct {
-	nullable bool
-	convert  func(src S) ([]int64, error)
-}
+// compileAccess returns access for indices into typ, reduced to a single Field or
+// FieldByIndex lookup if no pointers lie along the path, skipping the deref checks of
+// each segment. It only saves the walk: rows are still allocated and set via reflect.
+func compileAccess(typ reflect.Type, indices []int) func(dst reflect.Value) reflect.Value {
+	t := derefType(typ)
 
-func (s IntSliceScanner[S]) Asc() IntSliceScanner[S] {
-	return IntSliceScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) ([]int64, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return nil, err
+	for _, idx := range indices {
+		if t = t.Field(idx).Type; t.Kind() == reflect.Pointer {
+			return func(dst reflect.Value) reflect.Value {
+				return access(dst, indices)
 			}
+		}
+	}
 
-			slices.Sort(val)
+	switch len(indices) {
+	case 0:
+		return func(dst reflect.Value) reflect.Value { return dst }
+	case 1:
+		i := indices[0]
 
-			return val, nil
-		},
+		return func(dst reflect.Value) reflect.Value { return dst.Field(i) }
 	}
+
+	return func(dst reflect.Value) reflect.Value { return dst.FieldByIndex(indices) }
 }
 
-func (s IntSliceScanner[S]) Desc() IntSliceScanner[S] {
-	return IntSliceScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) ([]int64, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return nil, err
-			}
+func access(dst reflect.Value, indices []int) reflect.Value {
+	for _, idx := range indices {
+		dst = deref(dst).Field(idx)
+	}
 
-			slices.Sort(val)
-			slices.Reverse(val)
+	return deref(dst)
+}
 
-			return val, nil
-		},
+// destSetter returns the setter for dstType or, given a factory, one that sets the
+// interface dstType to a new value from factory after setting conv into it.
+func destSetter[C any](
+	dstType reflect.Type,
+	factory func() any,
+	setter func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error),
+) (func(dst reflect.Value, conv C) error, error) {
+	if factory == nil {
+		return fieldSetter(dstType, setter)
 	}
-}
 
-func (s IntSliceScanner[S]) Format(base int) StringSliceScanner[S] {
-	return StringSliceScanner[S]{
-		nullable: s.nullable,
-		convert: func(src S) ([]string, error) {
-			val, err := s.convert(src)
-			if err != nil {
-				return nil, err
-			}
+	if dstType.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("as: %s is not an interface", dstType)
+	}
 
-			conv := make([]string, len(val))
+	sample := factory()
+	if sample == nil {
+		return nil, errors.New("as: factory returned nil")
+	}
 
-			for i, v := range val {
-				conv[i] = strconv.FormatInt(v, base)
-			}
+	valType := reflect.TypeOf(sample)
+	if !valType.Implements(dstType) {
+		return nil, fmt.Errorf("as: %s does not implement %s", valType, dstType)
+	}
 
-			return conv, nil
-		},
+	set, err := fieldSetter(derefType(valType), setter)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (s IntSliceScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
+	return func(dst reflect.Value, conv C) error {
+		val := reflect.New(valType).Elem()
+		val.Set(reflect.ValueOf(factory()))
 
-func (s IntSliceScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
+		if err := set(deref(val), conv); err != nil {
+			return err
+		}
 
-var int64SliceType = reflect.TypeFor[[]int64]()
+		dst.Set(val)
 
-func (s IntSliceScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []int64) error, error) {
-	if dstType == int64SliceType {
-		return func(dst reflect.Value, conv []int64) error {
-			//nolint:forcetypeassert
-			*dst.Addr().Interface().(*[]int64) = conv
+		return nil
+	}, nil
+}
 
-			return nil
-		}, nil
+var sqlScannerType = reflect.TypeFor[sql.Scanner]()
+
+// fieldSetter returns the setter for dstType, falling back to the value field of the
+// sql.Null* types, such as sql.NullString, whose Valid it sets, and then to the Scan
+// method of sql.Scanner implementations, which receive the converted value.
+func fieldSetter[C any](
+	dstType reflect.Type,
+	setter func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error),
+) (func(dst reflect.Value, conv C) error, error) {
+	set, err := setter(dstType)
+	if err == nil {
+		return set, nil
 	}
 
-	if int64SliceType.ConvertibleTo(dstType) {
-		return func(dst reflect.Value, conv []int64) error {
-			dst.Set(reflect.ValueOf(conv).Convert(dstType))
+	if isNullType(dstType) {
+		if set, valErr := setter(dstType.Field(0).Type); valErr == nil {
+			return nullSetter(set), nil
+		}
+	}
 
-			return nil
+	if reflect.PointerTo(dstType).Implements(sqlScannerType) {
+		return func(dst reflect.Value, conv C) error {
+			//nolint:forcetypeassert
+			return dst.Addr().Interface().(sql.Scanner).Scan(conv)
 		}, nil
 	}
 
-	if dstType.Kind() == reflect.Slice {
-		//nolint:exhaustive
-		switch derefType(dstType.Elem()).Kind() {
-		case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
-			return func(dst reflect.Value, conv []int64) error {
-				dst.Set(reflect.MakeSlice(dstType, len(conv), len(conv)))
-
-				for i, v := range conv {
-					deref(dst.Index(i)).SetInt(v)
-				}
+	return nil, err
+}
 
-				return nil
-			}, nil
+func nullSetter[C any](set func(dst reflect.Value, conv C) error) func(dst reflect.Value, conv C) error {
+	return func(dst reflect.Value, conv C) error {
+		if err := set(dst.Field(0), conv); err != nil {
+			return err
 		}
-	}
 
-	return nil, fmt.Errorf("%s is not assignable to []int64 value", dstType)
-}
+		dst.Field(1).SetBool(true)
 
-type JSONScanner[S any] struct {
-	nullable bool
-	convert  func(src S) ([]byte, error)
+		return nil
+	}
 }
 
-func (s JSONScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
+func isNullType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.PkgPath() == "database/sql" && typ.NumField() == 2 &&
+		typ.Field(1).Name == "Valid" && typ.Field(1).Type.Kind() == reflect.Bool
 }
 
-func (s JSONScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
+// assign sets conv at the field returned by at, or at key of the map there if key is
+// valid, creating the map if it is nil.
+func assign[C any](dst reflect.Value, at func(dst reflect.Value) reflect.Value, key reflect.Value, set func(dst reflect.Value, conv C) error, conv C) error {
+	if !key.IsValid() {
+		return set(at(dst), conv)
+	}
 
-var jsonMessageType = reflect.TypeFor[json.RawMessage]()
+	m := at(dst)
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
 
-func (s JSONScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
-	if dstType == jsonMessageType {
-		return func(dst reflect.Value, conv []byte) error {
-			dst.SetBytes(conv)
+	elem := reflect.New(m.Type().Elem()).Elem()
 
-			return nil
-		}, nil
+	if err := set(deref(elem), conv); err != nil {
+		return err
 	}
 
-	return func(dst reflect.Value, conv []byte) error {
-		return json.Unmarshal(conv, dst.Addr().Interface())
-	}, nil
-}
+	m.SetMapIndex(key, elem)
 
-type TextScanner[S any] struct {
-	nullable bool
-	convert  func(src S) ([]byte, error)
+	return nil
 }
 
-func (s TextScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
+func setValue(dst reflect.Value, src reflect.Value) error {
+	dst.Set(src)
 
-func (s TextScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
+	return nil
 }
 
-var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
-
-func (s TextScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
-	if reflect.PointerTo(dstType).Implements(textUnmarshalerType) {
-		return func(dst reflect.Value, conv []byte) error {
-			//nolint:forcetypeassert
-			return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(conv)
-		}, nil
-	}
+func trimValue(dst reflect.Value, src reflect.Value) error {
+	dst.SetString(strings.TrimSpace(src.String()))
 
-	return nil, fmt.Errorf("%s doesn't implement encoding.TextUnmarshaler", dstType)
+	return nil
 }
 
-type BinaryScanner[S any] struct {
-	nullable bool
-	convert  func(src S) ([]byte, error)
-}
+// pruneIndices returns the indices of the nearest pointer field enclosing the
+// destination, or nil if there is none or the mode doesn't prune.
+func pruneIndices(mode nullMode, typ reflect.Type, indices []int) []int {
+	if mode != nullPrune || len(indices) == 0 {
+		return nil
+	}
 
-func (s BinaryScanner[S]) To(path string) Scanner {
-	return indirectScanFunc(s.nullable, s.setter, s.convert, path)
-}
+	var (
+		last int
+		t    = derefType(typ)
+	)
 
-func (s BinaryScanner[S]) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return s.To("").Scan(typ)
-}
+	for i, idx := range indices[:len(indices)-1] {
+		field := t.Field(idx)
 
-var binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+		if field.Type.Kind() == reflect.Pointer {
+			last = i + 1
+		}
 
-func (s BinaryScanner[S]) setter(dstType reflect.Type) (func(dst reflect.Value, conv []byte) error, error) {
-	if reflect.PointerTo(dstType).Implements(binaryUnmarshalerType) {
-		return func(dst reflect.Value, conv []byte) error {
-			//nolint:forcetypeassert
-			return dst.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(conv)
-		}, nil
+		t = derefType(field.Type)
 	}
 
-	return nil, fmt.Errorf("%s doesn't implement encoding.BinaryUnmarshaler", dstType)
-}
-
-type ScanFunc func(typ reflect.Type) (any, func(dst reflect.Value) error, error)
-
-func (sf ScanFunc) Scan(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-	return sf(typ)
+	return indices[:last]
 }
 
-func indirectScanFunc[S, C any](
-	nullable bool,
-	setter func(dstType reflect.Type) (func(dst reflect.Value, conv C) error, error),
-	convert func(src S) (C, error),
-	path string,
-) ScanFunc {
-	return func(typ reflect.Type) (any, func(dst reflect.Value) error, error) {
-		indices, dstType, err := accessor(typ, path)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		set, err := setter(dstType)
-		if err != nil {
-			if path != "" {
-				return nil, nil, fmt.Errorf("path %s: %w", path, err)
-			}
-
-			return nil, nil, err
+// nullReset returns the function applying mode to the destination at indices and key
+// for NULL columns. Pruning is left to the runner of p, if any, see Runner.pruneParents.
+func nullReset(mode nullMode, typ reflect.Type, indices []int, key reflect.Value, p *pruner) func(dst reflect.Value) {
+	switch mode {
+	case nullPrune:
+		if p != nil {
+			return func(reflect.Value) { p.requested = true }
 		}
 
-		if nullable {
-			var src sql.Null[S]
-
-			return &src, func(dst reflect.Value) error {
-				if !src.Valid {
-					return nil
-				}
+		prune := pruneIndices(mode, typ, indices)
 
-				conv, err := convert(src.V)
-				if err != nil {
-					return err
-				}
+		return func(dst reflect.Value) { pruneParent(dst, prune) }
+	case nullNil, nullZero:
+		return func(dst reflect.Value) { resetField(dst, indices, key) }
+	default:
+		return func(reflect.Value) {}
+	}
+}
 
-				return set(access(dst, indices), conv)
-			}, nil
-		}
+// nullCheck reports destinations at indices and key into typ that mode can't reset.
+func nullCheck(mode nullMode, typ reflect.Type, indices []int, key reflect.Value) error {
+	if mode != nullNil {
+		return nil
+	}
 
-		var src S
+	return nilable(fieldType(typ, indices, key))
+}
 
-		return &src, func(dst reflect.Value) error {
-			conv, err := convert(src)
-			if err != nil {
-				return err
-			}
+// fieldType returns the type of the field at indices into typ, or of its elements for
+// key.
+func fieldType(typ reflect.Type, indices []int, key reflect.Value) reflect.Type {
+	for _, idx := range indices {
+		typ = derefType(typ).Field(idx).Type
+	}
 
-			return set(access(dst, indices), conv)
-		}, nil
+	if key.IsValid() {
+		typ = derefType(typ).Elem()
 	}
+
+	return typ
 }
 
-func accessor(typ reflect.Type, path string) ([]int, reflect.Type, error) {
-	if path == "" {
-		return nil, derefType(typ), nil
+func nilable(dstType reflect.Type) error {
+	switch dstType.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return nil
+	default:
+		return fmt.Errorf("NullableNil requires a destination that can be nil, got %s", dstType)
 	}
+}
 
-	var indices []int
+// resetField sets the field at indices, or its entry for key, to the zero value,
+// unless a pointer enclosing it is nil.
+func resetField(dst reflect.Value, indices []int, key reflect.Value) {
+	field := dst
 
-	for p := range strings.SplitSeq(path, ".") {
-		sf, ok := derefType(typ).FieldByName(p)
+	if len(indices) > 0 {
+		parent, ok := lookup(dst, indices[:len(indices)-1])
 		if !ok {
-			return nil, nil, fmt.Errorf("path %s: not found", path)
+			return
 		}
 
-		if !sf.IsExported() {
-			return nil, nil, fmt.Errorf("path %s: not exported", path)
-		}
+		field = parent.Field(indices[len(indices)-1])
+	}
 
-		typ = sf.Type
+	if !key.IsValid() {
+		field.SetZero()
 
-		indices = append(indices, sf.Index...)
+		return
 	}
 
-	return indices, derefType(typ), nil
+	if m, ok := indirect(field); ok && !m.IsNil() {
+		m.SetMapIndex(key, reflect.Zero(m.Type().Elem()))
+	}
 }
 
-func derefType(t reflect.Type) reflect.Type {
-	for t.Kind() == reflect.Pointer {
-		t = t.Elem()
+func pruneParent(dst reflect.Value, indices []int) {
+	if len(indices) == 0 {
+		return
 	}
 
-	return t
+	if parent, ok := lookup(dst, indices[:len(indices)-1]); ok {
+		parent.Field(indices[len(indices)-1]).SetZero()
+	}
 }
 
-func deref(dst reflect.Value) reflect.Value {
-	for dst.Kind() == reflect.Pointer {
-		if dst.IsNil() {
-			dst.Set(reflect.New(dst.Type().Elem()))
+func indirect(src reflect.Value) (reflect.Value, bool) {
+	for src.Kind() == reflect.Pointer {
+		if src.IsNil() {
+			return reflect.Value{}, false
 		}
 
-		dst = dst.Elem()
+		src = src.Elem()
 	}
 
-	return dst
+	return src, true
 }
 
-func access(dst reflect.Value, indices []int) reflect.Value {
+func lookup(src reflect.Value, indices []int) (reflect.Value, bool) {
+	src, ok := indirect(src)
+
 	for _, idx := range indices {
-		dst = deref(dst).Field(idx)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		src, ok = indirect(src.Field(idx))
 	}
 
-	return deref(dst)
+	return src, ok
 }
@@ -1,11 +1,22 @@
 package structscan_test
 
 import (
+	"bytes"
+	"cmp"
+	"context"
 	"database/sql"
+	"encoding/gob"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"math/big"
+	"net"
+	"net/netip"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,10 +28,49 @@ type MyString string
 
 type MyInt64 int64
 
+// Celsius is a custom database type scanning integers and strings like "21C".
+type Celsius struct {
+	Degrees int64
+}
+
+func (c *Celsius) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		c.Degrees = v
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSuffix(v, "C"), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		c.Degrees = n
+	default:
+		return fmt.Errorf("unsupported celsius value %T", src)
+	}
+
+	return nil
+}
+
 type MyFloat64 float64
 
 type MyBool bool
 
+type Item struct {
+	ID int64 `json:"id"`
+}
+
+type Payload interface {
+	Kind() string
+}
+
+type EmailPayload struct {
+	To string `json:"to"`
+}
+
+func (*EmailPayload) Kind() string {
+	return "email"
+}
+
 type Data struct {
 	Time                 time.Time
 	Nested               *Data
@@ -29,7 +79,15 @@ type Data struct {
 	StringPointerPointer **string
 	StringPointer        *string
 	AnyMap               map[string]any
+	StringMap            map[string]string
+	IntMap               map[string]int
+	Values               url.Values
+	StringsMap           map[string][]string
+	Item                 Item
+	Items                []Item
+	Payload              Payload
 	BigIntPointer        *big.Int
+	BigRatPointer        *big.Rat
 	URLPointer           *url.URL
 	TimePointer          *time.Time
 	URL                  url.URL
@@ -38,7 +96,13 @@ type Data struct {
 	MyString             MyString
 	BigInt               big.Int
 	NullString           sql.Null[string]
+	NullInt64            sql.NullInt64
+	NullTime             *sql.NullTime
+	Celsius              Celsius
+	CelsiusPointer       *Celsius
 	Strings              []string
+	Uint64s              []uint64
+	Uint32s              []uint32
 	RawJSON              json.RawMessage
 	StringPointers       []*string
 	Bytes                []byte
@@ -66,6 +130,53 @@ func TestOne(t *testing.T) {
 	}
 
 	cases := []Case{
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().TrimSpace().To("NullString"),
+				structscan.Scan().String().ParseInt(10, 64).To("NullInt64"),
+				structscan.Scan().String().ParseTime(time.DateOnly).To("NullTime"),
+			},
+			SQL: "SELECT ' a ', '42', '2024-01-02'",
+			Expect: Data{
+				NullString: sql.Null[string]{V: "a", Valid: true},
+				NullInt64:  sql.NullInt64{Int64: 42, Valid: true},
+				NullTime:   &sql.NullTime{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+			},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ToUpper().To("Celsius"),
+				structscan.Scan().String().ParseInt(10, 64).To("CelsiusPointer"),
+			},
+			SQL:    "SELECT '21c', '-3'",
+			Expect: Data{Celsius: Celsius{Degrees: 21}, CelsiusPointer: &Celsius{Degrees: -3}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.TimeFlexible().To("Time"),
+				structscan.Scan().TimeFlexible().To("TimePointer"),
+			},
+			SQL: "SELECT '2024-01-02 10:00:00', 1704189600",
+			Expect: Data{
+				Time:        time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+				TimePointer: ptr(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Nullable().TimeFlexible("02.01.2006").To("Time"),
+				structscan.Nullable().TimeFlexible().To("TimePointer"),
+			},
+			SQL:    "SELECT '02.01.2024', NULL",
+			Expect: Data{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Nullable().Int().To("NullInt64"),
+			},
+			SQL:    "SELECT NULL",
+			Expect: Data{},
+		},
 		{
 			Scanners: []structscan.Scanner{
 				structscan.Scan().To("String"),
@@ -206,6 +317,193 @@ func TestOne(t *testing.T) {
 			SQL:    "SELECT 'hello,world'",
 			Expect: Data{Nested: &Data{StringPointers: []*string{ptr("hello"), ptr("world")}}},
 		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ParseKV(";", "=").To("StringMap"),
+			},
+			SQL:    `SELECT 'a=1; b = x=y ;'`,
+			Expect: Data{StringMap: map[string]string{"a": "1", "b": "x=y"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ParseKV(",", ":").To("IntMap"),
+			},
+			SQL:    `SELECT 'a:1,b:-2'`,
+			Expect: Data{IntMap: map[string]int{"a": 1, "b": -2}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ParseQuery().To("Values"),
+			},
+			SQL:    `SELECT 'a=1&a=2&b=x%20y'`,
+			Expect: Data{Values: url.Values{"a": {"1", "2"}, "b": {"x y"}}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ParseQuery().To("StringsMap"),
+			},
+			SQL:    `SELECT 'a=1'`,
+			Expect: Data{StringsMap: map[string][]string{"a": {"1"}}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().Split(",").Compact().Map(func(v string) (string, error) {
+					return strings.ToUpper(v), nil
+				}).Unique().Filter(func(v string) bool { return v != "C" }).To("Strings"),
+			},
+			SQL:    `SELECT 'a,,b,a,c,b'`,
+			Expect: Data{Strings: []string{"A", "B"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().Split(",").TrimSpaceEach().To("Strings"),
+			},
+			SQL:    `SELECT 'a , b , c'`,
+			Expect: Data{Strings: []string{"a", "b", "c"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().Split(",").ParseInt(10, 64).Sum().To("Int16"),
+				structscan.Scan().String().Split(",").ParseInt(10, 64).Min().To("MyInt64"),
+				structscan.Scan().String().Split(",").ParseInt(10, 64).Max().To("Uint64"),
+				structscan.Scan().String().Split(",").ParseInt(10, 64).Len().To("Float64"),
+			},
+			SQL:    `SELECT '3,-1,7', '3,-1,7', '3,-1,7', '3,-1,7'`,
+			Expect: Data{Int16: 9, MyInt64: -1, Uint64: 7, Float64: 3},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().Split(",").ParseUint(10, 64).Desc().To("Uint64s"),
+				structscan.Scan().String().Split(",").ParseUint(16, 32).To("Uint32s"),
+			},
+			SQL:    `SELECT '1,18446744073709551615,2', 'ff,ffffffff'`,
+			Expect: Data{Uint64s: []uint64{18446744073709551615, 2, 1}, Uint32s: []uint32{255, 4294967295}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().SplitRegexp(`\s*[,;]\s*`).To("Strings"),
+			},
+			SQL:    `SELECT 'a , b;c'`,
+			Expect: Data{Strings: []string{"a", "b", "c"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().SplitN(" ", 2).To("Array"),
+				structscan.Scan().String().Fields().To("Strings"),
+			},
+			SQL:    `SELECT 'A1 rest of description', '  a  b	c '`,
+			Expect: Data{Array: [2]string{"A1", "rest of description"}, Strings: []string{"a", "b", "c"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ToLower().To("String"),
+				structscan.Scan().String().ToUpper().To("MyString"),
+				structscan.Scan().String().Replace("-", "", 1).To("Nested.String"),
+				structscan.Scan().String().ReplaceAll("-", "").To("Nested.MyString"),
+			},
+			SQL:    `SELECT 'HeLLo', 'HeLLo', 'a-b-c', 'a-b-c'`,
+			Expect: Data{String: "hello", MyString: "HELLO", Nested: &Data{String: "ab-c", MyString: "abc"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().Transform(func(v string) (string, error) {
+					return strings.Map(func(r rune) rune {
+						if r == 'é' {
+							return 'e'
+						}
+
+						return r
+					}, v), nil
+				}).To("String"),
+			},
+			SQL:    `SELECT 'café'`,
+			Expect: Data{String: "cafe"},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().MaxRunes(3).To("String"),
+				structscan.Scan().String().MaxBytes(2).To("MyString"),
+				structscan.Scan().String().MaxRunes(10).To("Nested.String"),
+			},
+			SQL:    `SELECT 'héllo', 'héllo', 'héllo'`,
+			Expect: Data{String: "hél", MyString: "h", Nested: &Data{String: "héllo"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().Mask(0, 4, '*').To("String"),
+				structscan.Scan().String().Mask(1, 0, '•').To("MyString"),
+				structscan.Scan().String().Mask(2, 2, '*').To("Nested.String"),
+			},
+			SQL:    `SELECT '4111111111111111', 'jörg@example.com', 'abc'`,
+			Expect: Data{String: "************1111", MyString: "j•••••••••••••••", Nested: &Data{String: "***"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().To("StringMap.color"),
+				structscan.Scan().To("StringMap.size"),
+				structscan.Scan().Nullable().String().To("StringMap.missing"),
+				structscan.Scan().Int().To("IntMap.count"),
+				structscan.Scan().To("Nested.AnyMap.name"),
+			},
+			SQL: `SELECT 'red', 'XL', NULL, 3, 'x'`,
+			Expect: Data{
+				StringMap: map[string]string{"color": "red", "size": "XL"},
+				IntMap:    map[string]int{"count": 3},
+				Nested:    &Data{AnyMap: map[string]any{"name": "x"}},
+			},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ToFunc(func(dst *Data, v string) error {
+					dst.String = v + "!"
+
+					return nil
+				}),
+				structscan.Scan().Int().ToFunc(func(dst *Data, v MyInt64) error {
+					dst.MyInt64 = v * 2
+
+					return nil
+				}),
+				structscan.Scan().JSON().ToFunc(func(dst *Data, v *Item) error {
+					dst.Items = append(dst.Items, *v)
+
+					return nil
+				}),
+				structscan.Scan().Nullable().String().ToFunc(func(dst *Data, v string) error {
+					dst.MyString = "called"
+
+					return nil
+				}),
+				structscan.Scan().ToFunc(func(dst *Data, v []byte) error {
+					dst.Bytes = v
+
+					return nil
+				}),
+			},
+			SQL:    `SELECT 'hi', 21, '{"id":7}', NULL, x'0102'`,
+			Expect: Data{String: "hi!", MyInt64: 42, Items: []Item{{ID: 7}}, Bytes: []byte{1, 2}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().To("Payload").As(func() any { return &EmailPayload{} }),
+			},
+			SQL:    `SELECT '{"to":"a@example.com"}'`,
+			Expect: Data{Payload: &EmailPayload{To: "a@example.com"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ParseCSV(',').To("Strings"),
+			},
+			SQL:    `SELECT 'a,"b,c","d ""e"""'`,
+			Expect: Data{Strings: []string{"a", "b,c", `d "e"`}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().ParseCSV(';').To("Strings"),
+			},
+			SQL:    `SELECT ''`,
+			Expect: Data{Strings: []string{}},
+		},
 		{
 			Scanners: []structscan.Scanner{
 				structscan.Scan().String().ParseInt(10, 64).To("Int16"),
@@ -255,6 +553,27 @@ func TestOne(t *testing.T) {
 			SQL:    "SELECT '2200-01-07'",
 			Expect: Data{TimePointer: ptr(must(time.ParseInLocation(time.DateOnly, "2200-01-07", time.UTC)))},
 		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().Decimal().To("BigRatPointer"),
+			},
+			SQL:    "SELECT '1.25'",
+			Expect: Data{BigRatPointer: big.NewRat(5, 4)},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().String().TrimSpace().Decimal().To("BigRatPointer"),
+			},
+			SQL:    "SELECT '  -0.5  '",
+			Expect: Data{BigRatPointer: big.NewRat(-1, 2)},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().Decimal().To("BigIntPointer"),
+			},
+			SQL:    "SELECT '1234567890123'",
+			Expect: Data{BigIntPointer: big.NewInt(1234567890123)},
+		},
 	}
 
 	for _, c := range cases {
@@ -385,6 +704,67 @@ func TestFirst(t *testing.T) {
 			SQL:    `SELECT '{"hello":"moon"}'`,
 			Expect: Data{AnyMap: map[string]any{"hello": "moon"}},
 		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().Path("a.b[1].c").To("String"),
+			},
+			SQL:    `SELECT '{"a":{"b":[{"c":"x"},{"c":"y"}]}}'`,
+			Expect: Data{String: "y"},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().Path("[0]").To("AnyMap"),
+			},
+			SQL:    `SELECT '[{"first":"item"}]'`,
+			Expect: Data{AnyMap: map[string]any{"first": "item"}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().Path("a.missing[3]").To("String"),
+			},
+			SQL:    `SELECT '{"a":{}}'`,
+			Expect: Data{},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().WithUnmarshal(func(data []byte, v any) error {
+					//nolint:forcetypeassert
+					*v.(*map[string]any) = map[string]any{"raw": string(data)}
+
+					return nil
+				}).To("AnyMap"),
+			},
+			SQL:    `SELECT '{"a":1}'`,
+			Expect: Data{AnyMap: map[string]any{"raw": `{"a":1}`}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().MaxSize(64).UseNumber().To("AnyMap"),
+			},
+			SQL:    `SELECT '{"id":12345678901234567890}'`,
+			Expect: Data{AnyMap: map[string]any{"id": json.Number("12345678901234567890")}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().Unmarshal(xml.Unmarshal).To("Item"),
+			},
+			SQL:    `SELECT '<Item><ID>3</ID></Item>'`,
+			Expect: Data{Item: Item{ID: 3}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().Array().To("Items"),
+			},
+			SQL:    `SELECT ' [{"id":1},{"id":2}]'`,
+			Expect: Data{Items: []Item{{ID: 1}, {ID: 2}}},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().JSON().Strict().To("Item"),
+			},
+			SQL:    `SELECT '{"id":7}'`,
+			Expect: Data{Item: Item{ID: 7}},
+		},
 		{
 			Scanners: []structscan.Scanner{
 				structscan.Scan().String().To("String"),
@@ -708,6 +1088,39 @@ func TestAll(t *testing.T) {
 				{Nested: &Data{String: "nested2"}},
 			},
 		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().To("Nested.String"),
+				structscan.Scan().PruneOnNull().Int().To("Nested.Int16"),
+			},
+			SQL: `SELECT * FROM (VALUES ('x', 1), ('y', NULL));`,
+			Expect: []*Data{
+				{Nested: &Data{String: "x", Int16: 1}},
+				{Nested: &Data{String: "y"}},
+			},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().PruneOnNull().Int().To("Nested.Int16"),
+				structscan.Scan().Nullable().String().To("Nested.String"),
+			},
+			SQL: `SELECT * FROM (VALUES (1, 'x'), (NULL, 'y'), (NULL, NULL));`,
+			Expect: []*Data{
+				{Nested: &Data{String: "x", Int16: 1}},
+				{Nested: &Data{String: "y"}},
+				{},
+			},
+		},
+		{
+			Scanners: []structscan.Scanner{
+				structscan.Scan().PruneOnNull().To("Nested.Nested.String"),
+			},
+			SQL: `SELECT * FROM (VALUES ('x'), (NULL));`,
+			Expect: []*Data{
+				{Nested: &Data{Nested: &Data{String: "x"}}},
+				{},
+			},
+		},
 		{
 			Scanners: []structscan.Scanner{
 				structscan.Scan().To("Nested.Int16"),
@@ -750,14 +1163,3189 @@ func TestAll(t *testing.T) {
 	}
 }
 
-func ptr[T any](t T) *T {
-	return &t
+func TestNewErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]structscan.Scanner{
+		"unknown path":      {structscan.Scan().To("Unknown")},
+		"not assignable":    {structscan.Scan().Int().To("String")},
+		"json path bracket": {structscan.Scan().JSON().Path("a[0").To("AnyMap")},
+		"json path index":   {structscan.Scan().JSON().Path("a[x]").To("AnyMap")},
+		"json path empty":   {structscan.Scan().JSON().Path("a..b").To("AnyMap")},
+		"json array":        {structscan.Scan().JSON().Array().To("Item")},
+		"kv destination":    {structscan.Scan().String().ParseKV(",", "=").To("AnyMap")},
+		"query destination": {structscan.Scan().String().ParseQuery().To("StringMap")},
+		"split regexp":      {structscan.Scan().String().SplitRegexp("(").ParseInt(10, 64).Sum().To("Int16")},
+		"as interface":      {structscan.Scan().JSON().To("Item").As(func() any { return &Item{} })},
+		"as implements":     {structscan.Scan().JSON().To("Payload").As(func() any { return &Item{} })},
+		"duplicate":         {structscan.Scan().To("String"), structscan.Scan().String().To("String")},
+		"func signature":    {structscan.Scan().String().ToFunc(func(*Data, string) {})},
+		"func value":        {structscan.Scan().String().ToFunc(func(*Data, chan int) error { return nil })},
+		"map key":           {structscan.Scan().To("String.color")},
+		"max runes":         {structscan.Scan().String().MaxRunes(-1).To("String")},
+		"json max size":     {structscan.Scan().JSON().MaxSize(-1).To("AnyMap")},
+		"mask":              {structscan.Scan().String().Mask(-1, 0, '*').To("String")},
+		"nil unmarshal":     {structscan.Scan().Unmarshal(nil).To("Item")},
+		"json unmarshal":    {structscan.Scan().JSON().Strict().WithUnmarshal(json.Unmarshal).To("AnyMap")},
+	}
+
+	for name, scanners := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := structscan.New[Data](scanners...); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
 }
 
-func must[T any](t T, err error) T {
+func TestScanErrors(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
-	return t
+	type Case struct {
+		Scanners []structscan.Scanner
+		SQL      string
+	}
+
+	cases := []Case{
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().ParseInt(10, 64).To("Int16")},
+			SQL:      "SELECT 'abc'",
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().ParseBool().To("Celsius")},
+			SQL:      "SELECT 'true'",
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.TimeFlexible().To("Time")},
+			SQL:      "SELECT 'yesterday'",
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().Transform(func(string) (string, error) {
+				return "", errors.New("transform")
+			}).To("String")},
+			SQL: "SELECT 'abc'",
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().ToFunc(func(*Data, string) error {
+				return errors.New("func")
+			})},
+			SQL: "SELECT 'abc'",
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().JSON().Strict().To("Item")},
+			SQL:      `SELECT '{"id":1,"unknown":true}'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().JSON().Strict().To("Item")},
+			SQL:      `SELECT '{"id":1} {"id":2}'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().JSON().MaxSize(4).To("AnyMap")},
+			SQL:      `SELECT '{"a":1}'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().JSON().Array().To("Items")},
+			SQL:      `SELECT 'null'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().ParseCSV(',').To("Strings")},
+			SQL:      `SELECT 'a,"b'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().ParseKV(",", ":").To("IntMap")},
+			SQL:      `SELECT 'a:x'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().Split(",").ParseInt(10, 64).Min().To("Int16")},
+			SQL:      `SELECT ''`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().Split(",").ParseUint(10, 64).To("Uint32s")},
+			SQL:      `SELECT '4294967296'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().Split(",").ParseInt(10, 64).Sum().To("MyInt64")},
+			SQL:      `SELECT '9223372036854775807,1'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().Split(",").Map(func(string) (string, error) {
+				return "", errors.New("map error")
+			}).To("Strings")},
+			SQL: `SELECT 'a,b'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().ParseKV(",", ":").To("StringMap")},
+			SQL:      `SELECT 'a'`,
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().String().ParseCSV(',').To("Strings")},
+			SQL:      "SELECT 'a,b' || char(10) || 'c,d'",
+		},
+		{
+			Scanners: []structscan.Scanner{structscan.Scan().JSON().Array().To("Items")},
+			SQL:      `SELECT '{"id":1}'`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.SQL, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := structscan.New[Data](c.Scanners...)
+			if err != nil {
+				t.Fatal(c.SQL, err)
+			}
+
+			rows, err := db.Query(c.SQL)
+			if err != nil {
+				t.Fatal(c.SQL, err)
+			}
+
+			defer rows.Close()
+
+			if _, err = schema.One(rows); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestErrorContext(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Data](
+		structscan.Scan().String().To("String"),
+		structscan.Scan().String().ParseInt(10, 16).To("Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'a', '1' UNION ALL SELECT 'b', 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	_, err = schema.All(rows)
+	if err == nil || !strings.HasPrefix(err.Error(), "row 2, column 1 -> Data.Int16: ") {
+		t.Fatalf("expected error with context, got %v", err)
+	}
+
+	var fe *structscan.FieldError
+	if !errors.As(err, &fe) || fe.Path != "Int16" || fe.Column != 1 || fe.Row != 2 {
+		t.Fatalf("expected field error, got %#v", fe)
+	}
+
+	if !errors.Is(err, structscan.ErrConversion) {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+
+	anonymous, err := structscan.New[struct{ N int16 }](structscan.Scan().String().ParseInt(10, 16).To("N"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = anonymous.All(rows); err == nil || !strings.HasPrefix(err.Error(), "row 1, column 0 -> N: ") {
+		t.Fatalf("expected error with context, got %v", err)
+	}
+
+	pair, err := structscan.New2[Data, Data](
+		[]structscan.Scanner{structscan.Scan().String().To("String")},
+		[]structscan.Scanner{structscan.Scan().String().ParseInt(10, 16).To("Int16")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT 'a', 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = pair.All(rows); err == nil || !strings.HasPrefix(err.Error(), "row 1, column 1 -> Pair.Second.Int16: ") {
+		t.Fatalf("expected error with context, got %v", err)
+	}
+
+	if _, err = structscan.New[Data](structscan.Scan().To("Unknown")); !errors.Is(err, structscan.ErrPathNotFound) {
+		t.Fatalf("expected path not found error, got %v", err)
+	}
+
+	if _, err = structscan.New[Data](structscan.Scan().Int().To("String")); !errors.Is(err, structscan.ErrNotAssignable) {
+		t.Fatalf("expected not assignable error, got %v", err)
+	}
+}
+
+func TestAllLenient(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Data](
+		structscan.Scan().String().To("String"),
+		structscan.Scan().String().ParseInt(10, 16).To("Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'a', '1' UNION ALL SELECT 'b', 'x' UNION ALL SELECT NULL, '3' UNION ALL SELECT 'd', '4'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, rowErrs, err := schema.AllLenient(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Data{{String: "a", Int16: 1}, {String: "d", Int16: 4}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if len(rowErrs) != 2 || rowErrs[0].Row != 2 || rowErrs[1].Row != 3 || !errors.Is(rowErrs[0], structscan.ErrConversion) {
+		t.Fatalf("unexpected row errors: %v", rowErrs)
+	}
+
+	if msg := rowErrs[0].Error(); !strings.HasPrefix(msg, "row 2, column 1 -> ") {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+
+	wrapped := structscan.RowError{Row: 2, Err: fmt.Errorf("invalid: %w", &structscan.FieldError{Row: 2, Err: errors.New("x")})}
+	if msg := wrapped.Error(); !strings.HasPrefix(msg, "row 2: invalid: ") {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
+
+func TestRowErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Data](structscan.Scan().String().ParseInt(10, 16).To("Int16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var skipped []int
+
+	lenient, err := schema.With(structscan.WithRowErrorHandler(func(rowIndex int, err error) error {
+		skipped = append(skipped, rowIndex)
+
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '1' UNION ALL SELECT 'x' UNION ALL SELECT '3'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := lenient.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || !reflect.DeepEqual(skipped, []int{2}) {
+		t.Fatalf("unexpected result: %v, skipped: %v", result, skipped)
+	}
+
+	abort := errors.New("abort")
+
+	strict, err := schema.With(structscan.WithRowErrorHandler(func(int, error) error {
+		return abort
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT '1' UNION ALL SELECT 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = strict.All(rows); !errors.Is(err, abort) {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+}
+
+func TestDebug(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Data](
+		structscan.Scan().String().TrimSpace().To("String"),
+		structscan.Scan().Nullable().String().To("StringMap.color"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	schema.Debug(&buf)
+
+	rows, err := db.Query("SELECT ' a ', 'red'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.One(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `row 1, column 0 -> Data.String: " a " => "a"
+row 1, column 1 -> Data.StringMap.color: sql.Null[string]{V:"red", Valid:true} => "red"
+`
+	if buf.String() != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, buf.String())
+	}
+
+	schema.Debug(nil)
+	buf.Reset()
+
+	rows, err = db.Query("SELECT 'b', NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.One(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output, got %s", buf.String())
+	}
+}
+
+type ctxKey struct{}
+
+type parentKey struct{}
+
+type instrumentation struct {
+	ops     []string
+	rows    []int
+	errs    []error
+	trace   []any
+	parents []any
+}
+
+func (i *instrumentation) OnScanStart(ctx context.Context, op string) context.Context {
+	i.ops = append(i.ops, op)
+	i.parents = append(i.parents, ctx.Value(parentKey{}))
+
+	return context.WithValue(ctx, ctxKey{}, op)
+}
+
+func (i *instrumentation) OnScanEnd(ctx context.Context, rowCount int, err error) {
+	i.rows = append(i.rows, rowCount)
+	i.errs = append(i.errs, err)
+	i.trace = append(i.trace, ctx.Value(ctxKey{}))
+}
+
+func TestInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	inst := &instrumentation{}
+
+	schema, err := structscan.New[Data](structscan.Scan().To("Int16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema, err = schema.With(structscan.WithInstrumentation(inst)); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1 UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.AllContext(t.Context(), rows); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT 1 UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.One(rows); !errors.Is(err, structscan.ErrTooManyRows) {
+		t.Fatalf("expected too many rows, got %v", err)
+	}
+
+	if !reflect.DeepEqual(inst.ops, []string{"All", "One"}) || !reflect.DeepEqual(inst.rows, []int{2, 0}) ||
+		inst.errs[0] != nil || !errors.Is(inst.errs[1], structscan.ErrTooManyRows) ||
+		!reflect.DeepEqual(inst.trace, []any{"All", "One"}) {
+		t.Fatalf("unexpected instrumentation: %+v", inst)
+	}
+}
+
+func TestInstrumentationContext(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Data](structscan.Scan().To("Int16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(t.Context(), parentKey{}, "caller")
+
+	cases := map[string]func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error){
+		"All": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, err := schema.AllContext(ctx, rows)
+
+			return len(result), err
+		},
+		"One": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			_, err := schema.OneContext(ctx, rows)
+
+			return 1, err
+		},
+		"First": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			_, err := schema.FirstContext(ctx, rows)
+
+			return 1, err
+		},
+		"AllLimit": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, _, err := schema.AllLimitContext(ctx, rows, 1)
+
+			return len(result), err
+		},
+		"AllDistinct": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, err := schema.AllDistinctContext(ctx, rows, "Int16")
+
+			return len(result), err
+		},
+		"AllIndexed": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, _, err := schema.AllIndexedContext(ctx, rows, "Int16")
+
+			return len(result), err
+		},
+		"AllSorted": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, err := schema.AllSortedContext(ctx, rows, func(a, b Data) int { return int(a.Int16 - b.Int16) })
+
+			return len(result), err
+		},
+		"AllWhere": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, err := schema.AllWhereContext(ctx, rows, func(Data) bool { return true })
+
+			return len(result), err
+		},
+		"FastAll": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, err := schema.FastAllContext(ctx, rows)
+
+			return len(result), err
+		},
+		"AllColumnar": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, err := schema.AllColumnarContext(ctx, rows)
+
+			return result.Len, err
+		},
+		"WriteCSV": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			return schema.WriteCSVContext(ctx, rows, &bytes.Buffer{}, structscan.CSVOptions{})
+		},
+		"AllLenient": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, _, err := schema.AllLenientContext(ctx, rows)
+
+			return len(result), err
+		},
+		"AllPresence": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, _, err := schema.AllPresenceContext(ctx, rows)
+
+			return len(result), err
+		},
+		"AllClose": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			result, err := schema.AllCloseContext(ctx, rows.(structscan.RowsCloser))
+
+			return len(result), err
+		},
+		"OneClose": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			_, err := schema.OneCloseContext(ctx, rows.(structscan.RowsCloser))
+
+			return 1, err
+		},
+		"OneOrZero": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			_, _, err := schema.OneOrZeroContext(ctx, rows)
+
+			return 1, err
+		},
+		"MustAll": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			return len(schema.MustAllContext(ctx, rows)), nil
+		},
+		"MustOne": func(schema *structscan.Schema[Data], rows structscan.Rows) (int, error) {
+			schema.MustOneContext(ctx, rows)
+
+			return 1, nil
+		},
+	}
+
+	// wrappers report the scan they delegate to.
+	wrappers := map[string]string{
+		"AllClose": "All", "OneClose": "One", "OneOrZero": "One", "MustAll": "All", "MustOne": "One",
+	}
+
+	for name, scan := range cases {
+		op := cmp.Or(wrappers[name], name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			inst := &instrumentation{}
+
+			schema, err := schema.With(structscan.WithInstrumentation(inst))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := db.Query("SELECT 1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer rows.Close()
+
+			count, err := scan(schema, rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(inst.ops, []string{op}) || !reflect.DeepEqual(inst.rows, []int{count}) ||
+				!reflect.DeepEqual(inst.parents, []any{"caller"}) {
+				t.Fatalf("unexpected instrumentation: %+v", inst)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	var counters structscan.Counters
+
+	schema, err := structscan.New[Data](structscan.Scan().String().ParseInt(10, 16).To("Int16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema, err = schema.With(structscan.WithMetrics(&counters)); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '1' UNION ALL SELECT 'x' UNION ALL SELECT '3'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, _, err = schema.AllLenient(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if counters.RowsScanned.Load() != 3 || counters.RowsFailed.Load() != 1 || counters.PoolMisses.Load()+counters.PoolHits.Load() != 2 {
+		t.Fatalf("unexpected counters: %s", counters.String())
+	}
+
+	if errs := counters.ConversionErrors(); !reflect.DeepEqual(errs, map[string]int64{"Int16": 1}) {
+		t.Fatalf("unexpected conversion errors: %v", errs)
+	}
+
+	var published map[string]any
+	if err = json.Unmarshal([]byte(counters.String()), &published); err != nil || published["rows_scanned"] != 3.0 {
+		t.Fatalf("unexpected json: %s", counters.String())
+	}
+}
+
+type Tagged struct {
+	ID    int64             `db:"id"`
+	Name  string            `db:"user_name,omitempty"`
+	Attrs map[string]string `db:"-"`
+	Note  string
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Tagged](
+		structscan.Scan().To("ID"),
+		structscan.Scan().String().To("Name"),
+		structscan.Scan().To("Attrs.color"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := schema.Columns(structscan.ANSI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []string{`"id"`, `"user_name"`, `"color"`}; !reflect.DeepEqual(columns, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, columns)
+	}
+
+	columns, err = schema.Columns(structscan.MySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []string{"`id`", "`user_name`", "`color`"}; !reflect.DeepEqual(columns, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, columns)
+	}
+
+	schema, err = structscan.New[Tagged](structscan.Scan().To("Note"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = schema.Columns(structscan.ANSI); err == nil {
+		t.Fatal("expected missing tag error")
+	}
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if err = gob.NewEncoder(&buf).Encode(Item{ID: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Data](structscan.Scan().Gob().To("Nested.Item"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ?", buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Nested.Item.ID != 42 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[*Data](
+		structscan.Scan().To("String"),
+		structscan.Scan().To("Nested.Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = schema.Key(); err == nil {
+		t.Fatal("expected error for missing paths")
+	}
+
+	if _, err = schema.Key("Strings"); err == nil {
+		t.Fatal("expected error for non-comparable path")
+	}
+
+	single, err := schema.Key("String")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	composite, err := schema.Key("String", "Nested.Int16")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT * FROM (VALUES ('a', 1), ('a', 2), ('b', 1), ('a', 1));`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	results, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	singles := map[any]int{}
+	composites := map[any]int{}
+
+	for _, r := range results {
+		singles[single(r)]++
+		composites[composite(r)]++
+	}
+
+	if len(singles) != 2 || singles["a"] != 3 {
+		t.Fatalf("unexpected single keys: %v", singles)
+	}
+
+	if len(composites) != 3 {
+		t.Fatalf("unexpected composite keys: %v", composites)
+	}
+
+	if composite(&Data{String: "a"}) != composite(&Data{String: "a", Nested: &Data{}}) {
+		t.Fatal("nil pointer along a key path should yield the zero value")
+	}
+}
+
+func TestIdentityMap(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[*Data](
+		structscan.Scan().To("String"),
+		structscan.Scan().To("Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = must(structscan.New[Data]()).With(structscan.IdentityMap("String")); err == nil {
+		t.Fatal("expected error for non-pointer type")
+	}
+
+	schema, err = schema.With(structscan.IdentityMap("String"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT * FROM (VALUES ('a', 1), ('b', 2), ('a', 3));`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	results, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 || results[0] != results[2] || results[0] == results[1] {
+		t.Fatalf("unexpected identities: %v", results)
+	}
+
+	if results[2].Int16 != 1 {
+		t.Fatalf("expected first instance to be kept, got %v", *results[2])
+	}
+}
+
+func TestIntern(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.Scan().To("Int16"),
+		structscan.Scan().To("Nested.String"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = schema.With(structscan.Intern("String", "String")); err == nil {
+		t.Fatal("expected error for non-pointer field")
+	}
+
+	schema, err = schema.With(structscan.Intern("Nested", "String"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT * FROM (VALUES (1, 'a'), (2, 'b'), (3, 'a'));`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	results, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 || results[0].Nested != results[2].Nested || results[0].Nested == results[1].Nested {
+		t.Fatalf("unexpected interning: %v", results)
+	}
+}
+
+func TestSkipNullRows(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.Scan().Nullable().To("String"),
+		structscan.Scan().Nullable().Int().To("Int16"),
+		structscan.Scan().To("NullString"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err = schema.With(structscan.SkipNullRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT * FROM (VALUES ('a', 1, NULL), (NULL, NULL, NULL), (NULL, 2, NULL), (NULL, NULL, 'b'));`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	results, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []Data{
+		{String: "a", Int16: 1},
+		{Int16: 2},
+		{NullString: sql.Null[string]{V: "b", Valid: true}},
+	}
+
+	if !reflect.DeepEqual(expect, results) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, results)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New[Data](structscan.Scan().To("Nested.Nested.Nested.String"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = schema.With(structscan.MaxDepth(2))
+	if err == nil || !strings.Contains(err.Error(), "recursive type structscan_test.Data") {
+		t.Fatalf("expected recursive depth error, got %v", err)
+	}
+
+	_, err = structscan.New[Data](structscan.Scan().To("Nested.Nested.Nested.Nested.Nested.Nested.Nested.Nested.Nested.String"))
+	if err != nil {
+		t.Fatalf("expected no depth limit by default, got %v", err)
+	}
+
+	schema, err = schema.With(structscan.MaxDepth(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'deep'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Nested.Nested.Nested.String != "deep" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+type Base struct {
+	ID   int64
+	Name string
+}
+
+type Audit struct {
+	ID        int64
+	CreatedBy string
+}
+
+type audit struct {
+	UpdatedBy string
+}
+
+type Embedded struct {
+	Base
+	*Audit
+	*audit
+	Title string
+}
+
+func TestEmbedded(t *testing.T) {
+	t.Parallel()
+
+	_, err := structscan.New[Embedded](structscan.Scan().To("ID"))
+	if err == nil || !strings.Contains(err.Error(), "ambiguous field ID, use one of Base.ID, Audit.ID") {
+		t.Fatalf("expected ambiguity error, got %v", err)
+	}
+
+	_, err = structscan.New[Embedded](structscan.Scan().To("Base.Name"), structscan.Scan().To("Name"))
+	if err == nil || !strings.Contains(err.Error(), "duplicate destination, already set by Base.Name") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	_, err = structscan.New[Embedded](structscan.Scan().To("UpdatedBy"))
+	if err == nil || !strings.Contains(err.Error(), "unexported embedded pointer") {
+		t.Fatalf("expected unexported embedded error, got %v", err)
+	}
+
+	schema, err := structscan.New[Embedded](
+		structscan.Scan().To("Base.ID"),
+		structscan.Scan().To("Name"),
+		structscan.Scan().To("Audit.ID"),
+		structscan.Scan().To("CreatedBy"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'name', 2, 'admin'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Embedded{Base: Base{ID: 1, Name: "name"}, Audit: &Audit{ID: 2, CreatedBy: "admin"}}
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
+
+func must[T any](t T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func TestLoosePaths(t *testing.T) {
+	t.Parallel()
+
+	if _, err := structscan.New[Embedded](structscan.Scan().To("title")); !errors.Is(err, structscan.ErrPathNotFound) {
+		t.Fatalf("expected ErrPathNotFound, got %v", err)
+	}
+
+	schema, err := structscan.NewWith[Embedded](
+		[]structscan.Option{structscan.WithLoosePaths(), structscan.PathAlias("creator", "Audit.CreatedBy")},
+		structscan.Scan().To("base.id"),
+		structscan.Scan().To("NAME"),
+		structscan.Scan().To("title"),
+		structscan.Scan().To("creator"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'name', 'title', 'admin'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Embedded{Base: Base{ID: 1, Name: "name"}, Audit: &Audit{CreatedBy: "admin"}, Title: "title"}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	_, err = structscan.NewWith[Embedded]([]structscan.Option{structscan.WithLoosePaths()},
+		structscan.Scan().To("name"), structscan.Scan().To("Base.Name"))
+	if err == nil || !strings.Contains(err.Error(), "duplicate destination") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	tagged, err := structscan.NewWith[Tagged](
+		[]structscan.Option{structscan.WithLoosePaths(), structscan.PathAlias("uname", "Name")},
+		structscan.Scan().To("id"),
+		structscan.Scan().String().To("uname"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := tagged.Columns(structscan.ANSI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []string{`"id"`, `"user_name"`}; !reflect.DeepEqual(columns, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, columns)
+	}
+}
+
+func TestLocationUnixAndLayouts(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	schema, err := structscan.NewWith[Data](
+		[]structscan.Option{structscan.WithLocation(loc)},
+		structscan.Scan().TimeFlexible().To("Time"),
+		structscan.Scan().TimeFlexible().To("TimePointer"),
+		structscan.Scan().String().ParseTime("02.01.2006 15h").To("Nested.Time"),
+		structscan.Scan().String().ParseTime("2006-01-02 15h -07").Format(time.RFC3339).To("String"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1704189600, 1704189600.5, '02.01.2024 10h', '2024-01-02 10h -05'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Data{
+		Time:        time.Date(2024, 1, 2, 12, 0, 0, 0, loc),
+		TimePointer: ptr(time.Date(2024, 1, 2, 12, 0, 0, 5e8, loc)),
+		Nested:      &Data{Time: time.Date(2024, 1, 2, 10, 0, 0, 0, loc)},
+		String:      "2024-01-02T10:00:00-05:00",
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	utc, err := structscan.New[Data](
+		structscan.Scan().String().ParseTime(time.DateTime).To("Time"),
+		structscan.Scan().String().ParseTime(time.RFC3339).To("TimePointer"),
+		structscan.Scan().TimeFlexible().To("Nested.Time"),
+		structscan.Scan().String().ParseTime(time.DateTime).Format(time.RFC3339).To("String"),
+		structscan.Scan().String().ParseTime(time.DateTime).If(func(v time.Time) bool {
+			return v.Location() == time.UTC
+		}).Then(func(s structscan.TimeScanner[time.Time]) structscan.Scanner {
+			return s.Convert(func(time.Time) (bool, error) { return true, nil })
+		}).Else(func(s structscan.TimeScanner[time.Time]) structscan.Scanner {
+			return s.Convert(func(time.Time) (bool, error) { return false, nil })
+		}).To("Bool"),
+		structscan.Scan().String().ParseTime(time.DateTime).Convert(func(v time.Time) (int16, error) {
+			_, offset := v.Zone()
+
+			return int16(offset / 3600), nil
+		}).To("Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := utc.With(structscan.WithLocation(loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := "SELECT '2024-01-02 10:00:00', '2024-01-02T10:00:00Z', '2024-01-02 10:00:00', '2024-01-02 10:00:00', '2024-01-02 10:00:00', '2024-01-02 10:00:00'"
+
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Data{
+		Time:        time.Date(2024, 1, 2, 10, 0, 0, 0, loc),
+		TimePointer: ptr(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)),
+		Nested:      &Data{Time: time.Date(2024, 1, 2, 10, 0, 0, 0, loc)},
+		String:      "2024-01-02T10:00:00+02:00",
+		Int16:       2,
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	rows, err = db.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err = utc.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect = Data{
+		Time:        time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+		TimePointer: ptr(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)),
+		Nested:      &Data{Time: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		String:      "2024-01-02T10:00:00Z",
+		Bool:        true,
+	}
+
+	if !reflect.DeepEqual(result, expect) || result.Time.Location() != time.UTC {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	first, err := structscan.LoadLocation("UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second, _ := structscan.LoadLocation("UTC"); first != second {
+		t.Fatal("expected cached location")
+	}
+
+	if _, err = structscan.LoadLocation("Nowhere/Unknown"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// TestDefaults is not parallel since Defaults affects all schemas created meanwhile.
+func TestDefaults(t *testing.T) {
+	structscan.Defaults(structscan.TrimStrings(), structscan.NullableByDefault())
+	defer structscan.Defaults()
+
+	schema, err := structscan.New[Data](
+		structscan.Scan().To("String"),
+		structscan.Scan().String().ToUpper().To("MyString"),
+		structscan.Scan().Int().To("Int16"),
+		structscan.Scan().To("StringPointer"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ' a ', ' b ', NULL, NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (Data{String: "a", MyString: "B"}); !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	structscan.Defaults()
+
+	schema, err = structscan.New[Data](structscan.Scan().Int().To("Int16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.One(rows); err == nil {
+		t.Fatal("expected error without defaults")
+	}
+}
+
+func TestExistsCount(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for query, expect := range map[string]bool{
+		"SELECT 1":                true,
+		"SELECT 1 WHERE 1 = 0":    false,
+		"SELECT 1 UNION SELECT 2": true,
+	} {
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exists, err := structscan.Exists(rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exists != expect {
+			t.Fatalf("%s: expected %t, got %t", query, expect, exists)
+		}
+
+		rows.Close()
+	}
+
+	rows, err := db.Query("SELECT count(*) FROM (SELECT 1 UNION SELECT 2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if n, err := structscan.Count(rows); err != nil || n != 2 {
+		t.Fatalf("expected 2, got %d, %v", n, err)
+	}
+
+	rows, err = db.Query("SELECT 1 UNION SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = structscan.Count(rows); !errors.Is(err, structscan.ErrTooManyRows) {
+		t.Fatalf("expected ErrTooManyRows, got %v", err)
+	}
+}
+
+func TestAllLimit(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.Value[int64]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for limit, expect := range map[int]struct {
+		Result    []int64
+		Truncated bool
+	}{
+		0: {Truncated: true},
+		2: {Result: []int64{1, 2}, Truncated: true},
+		3: {Result: []int64{1, 2, 3}},
+		5: {Result: []int64{1, 2, 3}},
+	} {
+		rows, err := db.Query("SELECT 1 UNION ALL SELECT 2 UNION ALL SELECT 3")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, truncated, err := schema.AllLimit(rows, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows.Close()
+
+		if !reflect.DeepEqual(result, expect.Result) || truncated != expect.Truncated {
+			t.Fatalf("limit %d: expected %v, %t, got %v, %t", limit, expect.Result, expect.Truncated, result, truncated)
+		}
+	}
+}
+
+func TestAllDistinct(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Base](structscan.Scan().To("ID"), structscan.Scan().To("Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'a' UNION ALL SELECT 2, 'b' UNION ALL SELECT 1, 'c'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.AllDistinct(rows, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Base{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if _, err = schema.AllDistinct(rows, "Unknown"); !errors.Is(err, structscan.ErrPathNotFound) {
+		t.Fatalf("expected ErrPathNotFound, got %v", err)
+	}
+}
+
+func TestAllIndexed(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Base](structscan.Scan().To("ID"), structscan.Scan().To("Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 3, 'a' UNION ALL SELECT 1, 'b' UNION ALL SELECT 3, 'c'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, index, err := schema.AllIndexed(rows, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Base{{ID: 3, Name: "a"}, {ID: 1, Name: "b"}, {ID: 3, Name: "c"}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if expect := map[any]int{int64(3): 0, int64(1): 1}; !reflect.DeepEqual(index, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, index)
+	}
+}
+
+func TestAllSorted(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Base](structscan.Scan().To("ID"), structscan.Scan().To("Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'b' UNION ALL SELECT 2, 'A' UNION ALL SELECT 3, 'a'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.AllSorted(rows, func(a, b Base) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Base{{ID: 2, Name: "A"}, {ID: 3, Name: "a"}, {ID: 1, Name: "b"}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestAllWhere(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Base](structscan.Scan().To("ID"), structscan.Scan().To("Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'ab' UNION ALL SELECT 2, 'b' UNION ALL SELECT 3, 'ba'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.AllWhere(rows, func(b Base) bool { return strings.Contains(b.Name, "a") })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Base{{ID: 1, Name: "ab"}, {ID: 3, Name: "ba"}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestMust(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := structscan.MustNew[Base](structscan.Scan().To("ID"), structscan.Scan().To("Name"))
+
+	rows, err := db.Query("SELECT 1, 'a'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if result := schema.MustOne(rows); result != (Base{ID: 1, Name: "a"}) {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	rows, err = db.Query("SELECT 1, 'a' UNION ALL SELECT 2, 'b'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if result := schema.MustAll(rows); len(result) != 2 {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	for name, fn := range map[string]func(){
+		"new": func() { structscan.MustNew[Base](structscan.Scan().To("Unknown")) },
+		"one": func() {
+			rows, err := db.Query("SELECT 1, 'a' WHERE 1 = 0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer rows.Close()
+
+			schema.MustOne(rows)
+		},
+		"all": func() {
+			rows, err := db.Query("SELECT 'x', 'a'")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer rows.Close()
+
+			schema.MustAll(rows)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic")
+				}
+			}()
+
+			fn()
+		})
+	}
+}
+
+func TestOneOrZero(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := structscan.MustNew[Base](structscan.Scan().To("ID"), structscan.Scan().To("Name"))
+
+	for query, expect := range map[string]struct {
+		Result Base
+		Found  bool
+		Err    bool
+	}{
+		"SELECT 1, 'a'":                     {Result: Base{ID: 1, Name: "a"}, Found: true},
+		"SELECT 1, 'a' WHERE 1 = 0":         {},
+		"SELECT 1, 'a' UNION SELECT 2, 'b'": {Result: Base{ID: 1, Name: "a"}, Err: true},
+	} {
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, found, err := schema.OneOrZero(rows)
+
+		rows.Close()
+
+		if result != expect.Result || found != expect.Found || (err != nil) != expect.Err {
+			t.Fatalf("%s: unexpected %v, %t, %v", query, result, found, err)
+		}
+	}
+}
+
+func TestExtraRows(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.MustNew[Base](structscan.Scan().To("ID")).With(structscan.IgnoreExtraRows(), structscan.DrainRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	schema.Debug(&buf)
+
+	rows, err := db.Query("SELECT 1 UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if result, err := schema.One(rows); err != nil || result.ID != 1 {
+		t.Fatalf("expected first row, got %v, %v", result, err)
+	}
+
+	if !strings.Contains(buf.String(), "ignoring rows after row 1") {
+		t.Fatalf("expected debug note, got %q", buf.String())
+	}
+
+	rows, err = db.Query("SELECT 1 UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.First(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if rows.Next() {
+		t.Fatal("expected drained rows")
+	}
+}
+
+type closeRows struct {
+	structscan.Rows
+
+	closed bool
+	err    error
+}
+
+func (c *closeRows) Close() error {
+	c.closed = true
+
+	return c.err
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := structscan.MustNew[Base](structscan.Scan().To("ID"))
+
+	rows, err := db.Query("SELECT 1 UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := schema.AllClose(rows)
+	if err != nil || len(result) != 2 {
+		t.Fatalf("unexpected %v, %v", result, err)
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errClose := errors.New("close")
+	closer := &closeRows{Rows: rows, err: errClose}
+
+	defer rows.Close()
+
+	if _, err = schema.OneClose(closer); !errors.Is(err, errClose) || !closer.closed {
+		t.Fatalf("expected close error, got %v", err)
+	}
+
+	rows, err = db.Query("SELECT 1 WHERE 1 = 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closer = &closeRows{Rows: rows}
+
+	defer rows.Close()
+
+	if _, err = schema.OneClose(closer); !errors.Is(err, sql.ErrNoRows) || !closer.closed {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestPoolOptions(t *testing.T) {
+	t.Parallel()
+
+	var unpooled structscan.Counters
+
+	schema, err := structscan.MustNew[Base](structscan.Scan().To("ID")).With(structscan.WithMetrics(&unpooled), structscan.WithoutPool())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		runner, err := schema.GetRunner()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		schema.PutRunner(runner)
+	}
+
+	if hits, misses := unpooled.PoolHits.Load(), unpooled.PoolMisses.Load(); hits != 0 || misses != 4 {
+		t.Fatalf("expected 0 hits and 4 misses, got %d and %d", hits, misses)
+	}
+
+	var bounded structscan.Counters
+
+	schema, err = structscan.MustNew[Base](structscan.Scan().To("ID")).With(structscan.WithMetrics(&bounded), structscan.PoolSize(1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := schema.GetRunner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := schema.GetRunner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema.PutRunner(first)
+	schema.PutRunner(second)
+
+	third, err := schema.GetRunner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if third != first {
+		t.Fatal("expected the pooled runner")
+	}
+
+	if hits, misses := bounded.PoolHits.Load(), bounded.PoolMisses.Load(); hits != 3 || misses != 1 {
+		t.Fatalf("expected 3 hits and 1 miss, got %d and %d", hits, misses)
+	}
+}
+
+func TestFastAll(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := structscan.MustNew[Data](
+		structscan.Scan().To("String"),
+		structscan.Nullable().To("Nested.Int16"),
+		structscan.Scan().String().Split(",").To("Strings"),
+	)
+
+	query := "SELECT 'a', 1, 'x,y' UNION ALL SELECT 'b', NULL, 'z'"
+
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	expect, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.FastAll(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	pointers := structscan.MustNew[*Base](structscan.Scan().To("ID"))
+
+	rows, err = db.Query("SELECT 1 UNION ALL SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	pointerResult, err := pointers.FastAll(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pointerResult) != 2 || pointerResult[0].ID != 1 || pointerResult[1].ID != 2 {
+		t.Fatalf("unexpected result %v", pointerResult)
+	}
+}
+
+func TestExpectDBType(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("CREATE TABLE expected (id INTEGER, name DECIMAL); INSERT INTO expected VALUES (1, '1.50')")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Base](
+		structscan.Scan().To("ID").ExpectDBType("integer"),
+		structscan.Scan().To("Name").ExpectDBType("TEXT", "VARCHAR"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT id, name FROM expected")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = schema.All(rows)
+	if !errors.Is(err, structscan.ErrColumnType) || !strings.Contains(err.Error(), `column 1 (name): unexpected column type: database type "DECIMAL"`) {
+		t.Fatalf("expected ErrColumnType, got %v", err)
+	}
+
+	if err = rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT id, name FROM expected")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = schema.AllLimit(rows, 1); !errors.Is(err, structscan.ErrColumnType) {
+		t.Fatalf("expected ErrColumnType, got %v", err)
+	}
+
+	if err = rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = schema.Check(t.Context(), db, "SELECT id, name FROM expected"); !errors.Is(err, structscan.ErrColumnType) {
+		t.Fatalf("expected ErrColumnType, got %v", err)
+	}
+
+	schema, err = structscan.New[Base](
+		structscan.Scan().To("ID").ExpectScanType(reflect.TypeFor[int64]()),
+		structscan.Scan().To("Name").ExpectDBType("DECIMAL"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT id, name FROM expected")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (Base{ID: 1, Name: "1.5"}); result != expect {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestNullableChain(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Base](
+		structscan.String().TrimSpace().Nullable().ParseInt(10, 64).To("ID"),
+		structscan.Scan().String().Nullable().ToUpper().To("Name"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ' 1 ', 'a' UNION ALL SELECT NULL, NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Base{{ID: 1, Name: "A"}, {}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestNullableNil(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = structscan.New[Data](structscan.NullableNil().To("Time")); !errors.Is(err, structscan.ErrNotAssignable) {
+		t.Fatalf("expected ErrNotAssignable, got %v", err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.NullableNil().To("StringPointer"),
+		structscan.NullableNil().String().Split(",").To("Strings"),
+		structscan.NullableNil().To("Nested.Int32Pointer"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := schema.GetRunner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer schema.PutRunner(runner)
+
+	rows, err := db.Query("SELECT NULL, NULL, NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+
+	if err = rows.Scan(runner.Src...); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		i32    = int32(1)
+		result = Data{StringPointer: ptr("old"), Strings: []string{"old"}, Nested: &Data{Int32Pointer: &i32}}
+	)
+
+	for _, set := range runner.Set {
+		if err = set(reflect.ValueOf(&result).Elem()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if result.StringPointer != nil || result.Strings != nil || result.Nested == nil || result.Nested.Int32Pointer != nil {
+		t.Fatalf("expected nil fields, got %+v", result)
+	}
+}
+
+func TestNullableZero(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.NullableZero().To("String"),
+		structscan.NullableZero().String().ParseInt(10, 64).To("IntMap.a"),
+		structscan.NullableZero().ToFunc(func(d *Data, v int16) error {
+			d.Int16 = v + 1
+
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := schema.GetRunner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer schema.PutRunner(runner)
+
+	rows, err := db.Query("SELECT NULL, NULL, NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+
+	if err = rows.Scan(runner.Src...); err != nil {
+		t.Fatal(err)
+	}
+
+	result := Data{String: "old", IntMap: map[string]int{"a": 1}, Int16: 5}
+
+	for _, set := range runner.Set {
+		if err = set(reflect.ValueOf(&result).Elem()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect := Data{IntMap: map[string]int{"a": 0}, Int16: 1}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestAnyCase(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = structscan.New[Data](structscan.Any().Case(func(v any) int { return 0 }).To("String")); err == nil {
+		t.Fatal("expected error for invalid case func")
+	}
+
+	if _, err = structscan.New[Data](structscan.Any().Case(func(v any) (int, error) { return 0, nil }).To("String")); !errors.Is(err, structscan.ErrNotAssignable) {
+		t.Fatalf("expected ErrNotAssignable, got %v", err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.Any().Case(func(v any) (string, error) {
+			switch v := v.(type) {
+			case int64:
+				return strconv.FormatInt(v, 10), nil
+			case float64:
+				return strconv.FormatFloat(v, 'f', -1, 64), nil
+			case []byte:
+				return string(v), nil
+			case string:
+				return v, nil
+			default:
+				return "", fmt.Errorf("unexpected %T", v)
+			}
+		}).To("MyString"),
+		structscan.Nullable().Any().To("AnyMap.raw"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 1 UNION ALL SELECT 2.5, 'a' UNION ALL SELECT 'three', NULL UNION ALL SELECT x'34', 2.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []Data{
+		{MyString: "1", AnyMap: map[string]any{"raw": int64(1)}},
+		{MyString: "2.5", AnyMap: map[string]any{"raw": "a"}},
+		{MyString: "three"},
+		{MyString: "4", AnyMap: map[string]any{"raw": 2.5}},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = structscan.New[Data](structscan.String().Convert(func(v int64) (bool, error) { return v != 0, nil }).To("Bool")); err == nil {
+		t.Fatal("expected error for mismatched convert func")
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.String().TrimSpace().Convert(time.ParseDuration).To("Duration"),
+		structscan.Int().Convert(func(v int64) (bool, error) { return v%2 == 0, nil }).To("Bool"),
+		structscan.String().Split(",").Convert(func(v []string) (int16, error) { return int16(len(v)), nil }).To("Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ' 1m30s ', 4, 'a,b,c'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (Data{Duration: 90 * time.Second, Bool: true, Int16: 3}); !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = structscan.New[Data](structscan.ConvertTo(structscan.Int(), func(v int64) (string, error) {
+		return "", nil
+	}).To("Bool")); err == nil {
+		t.Fatal("expected error for unassignable destination")
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.ConvertTo(structscan.String().TrimSpace(), time.ParseDuration).To("Duration"),
+		structscan.ConvertTo(structscan.Int(), func(v int64) (bool, error) { return v%2 == 0, nil }).To("Bool"),
+		structscan.ConvertTo(structscan.String().Split(","), func(v []string) (int16, error) {
+			if len(v) > 3 {
+				return 0, errors.New("too many")
+			}
+
+			return int16(len(v)), nil
+		}).To("Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ' 1m30s ', 4, 'a,b,c' UNION ALL SELECT '1s', 1, 'a,b,c,d'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if !errors.Is(err, structscan.ErrConversion) || !strings.Contains(err.Error(), "too many") {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+
+	if result != nil {
+		t.Fatalf("unexpected result: %v", result)
+	}
+
+	rows, err = db.Query("SELECT ' 1m30s ', 4, 'a,b,c'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	one, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (Data{Duration: 90 * time.Second, Bool: true, Int16: 3}); !reflect.DeepEqual(one, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, one)
+	}
+}
+
+func TestWithSetter(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	complexSetter := func(dstType reflect.Type) (func(dst reflect.Value, conv string) error, error) {
+		if dstType.Kind() != reflect.Complex64 {
+			return nil, fmt.Errorf("%s is not complex64", dstType)
+		}
+
+		return func(dst reflect.Value, conv string) error {
+			c, err := strconv.ParseComplex(conv, 64)
+			if err != nil {
+				return err
+			}
+
+			dst.SetComplex(c)
+
+			return nil
+		}, nil
+	}
+
+	if _, err = structscan.New[Data](structscan.String().To("Complex64")); !errors.Is(err, structscan.ErrNotAssignable) {
+		t.Fatalf("expected ErrNotAssignable, got %v", err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.String().WithSetter(complexSetter).To("Complex64"),
+		structscan.String().TrimSpace().WithSetter(complexSetter).To("String"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '1+2i', ' a '")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (Data{Complex64: 1 + 2i, String: "a"}); !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestIf(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels := map[string]int16{"low": 1, "high": 9}
+
+	numeric := func(v string) bool {
+		_, err := strconv.Atoi(v)
+
+		return err == nil
+	}
+
+	if _, err = structscan.New[Data](structscan.String().If(numeric).Then(func(structscan.StringScanner[string]) structscan.Scanner {
+		return structscan.Int()
+	}).To("Int16")); err == nil {
+		t.Fatal("expected error for branch of another source type")
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.Nullable().String().TrimSpace().If(numeric).Then(func(s structscan.StringScanner[string]) structscan.Scanner {
+			return s.ParseInt(10, 16)
+		}).Else(func(s structscan.StringScanner[string]) structscan.Scanner {
+			return s.ToLower().Convert(func(v string) (int16, error) {
+				level, ok := levels[v]
+				if !ok {
+					return 0, fmt.Errorf("unknown level %q", v)
+				}
+
+				return level, nil
+			})
+		}).To("Int16"),
+		structscan.Int().If(func(v int64) bool { return v > 0 }).Then(func(s structscan.IntScanner[int64]) structscan.Scanner {
+			return s.Format(10)
+		}).Else(func(s structscan.IntScanner[int64]) structscan.Scanner {
+			return s.Convert(func(int64) (string, error) { return "none", nil })
+		}).To("String"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ' 5 ', 1 UNION ALL SELECT 'High', 0 UNION ALL SELECT NULL, 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []Data{{Int16: 5, String: "1"}, {Int16: 9, String: "none"}, {String: "2"}}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestOr(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.String().ParseTime(time.RFC3339).Or(structscan.String().ParseTime(time.DateOnly)).To("Time"),
+		structscan.String().ParseInt(10, 64).Or(structscan.String().ParseInt(16, 64)).To("MyInt64"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '2024-01-02T03:04:05Z', '10' UNION ALL SELECT '2024-01-02', 'ff'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []Data{
+		{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), MyInt64: 10},
+		{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), MyInt64: 255},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	rows, err = db.Query("SELECT '02.01.2024', 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.All(rows); !errors.Is(err, structscan.ErrConversion) || !strings.Contains(err.Error(), `"02.01.2024"`) {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+}
+
+func TestOnError(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bad []string
+
+	schema, err := structscan.New[Data](
+		structscan.String().ParseInt(10, 16).OnError(-1, func(src string, _ error) { bad = append(bad, src) }).To("Int16"),
+		structscan.String().ParseBool().OnError(true).To("Bool"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '1', 'false' UNION ALL SELECT 'x', 'maybe' UNION ALL SELECT '', 'f'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Data{{Int16: 1}, {Int16: -1, Bool: true}, {Int16: -1}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if expect := []string{"x", ""}; !reflect.DeepEqual(bad, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, bad)
+	}
+}
+
+func TestOnErrorCopiesDefault(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := []string{" b ", " a "}
+
+	schema, err := structscan.New[Data](
+		structscan.String().Split(",").Map(func(v string) (string, error) {
+			return "", errors.New("bad " + v)
+		}).OnError(def).TrimSpaceEach().Asc().To("Strings"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'x' UNION ALL SELECT 'y'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []Data{{Strings: []string{"a", "b"}}, {Strings: []string{"a", "b"}}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	result[0].Strings[0] = "c"
+
+	if result[1].Strings[0] != "a" {
+		t.Fatalf("rows share the default: %v", result[1].Strings)
+	}
+
+	if expect := []string{" b ", " a "}; !reflect.DeepEqual(def, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, def)
+	}
+}
+
+type codeError struct {
+	code string
+	err  error
+}
+
+func (e codeError) Error() string { return e.code + ": " + e.err.Error() }
+
+func (e codeError) Unwrap() error { return e.err }
+
+func TestWithErrorWrapper(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.NewWith[Data](
+		[]structscan.Option{structscan.WithErrorWrapper(func(path string, col int, err error) error {
+			return codeError{code: fmt.Sprintf("invalid_%s_%d", strings.ToLower(path), col), err: err}
+		})},
+		structscan.Scan().To("String"),
+		structscan.String().ParseInt(10, 16).To("Int16"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'a', 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	_, err = schema.All(rows)
+
+	var (
+		ce codeError
+		fe *structscan.FieldError
+	)
+
+	if !errors.As(err, &ce) || ce.code != "invalid_int16_1" {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+
+	if !errors.As(err, &fe) || fe.Row != 1 || !errors.Is(err, structscan.ErrConversion) {
+		t.Fatalf("expected field error, got %v", err)
+	}
+}
+
+func TestWithErrorWrapperNil(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.NewWith[Data](
+		[]structscan.Option{structscan.WithErrorWrapper(func(string, int, error) error { return nil })},
+		structscan.String().ParseInt(10, 16).To("Int16"),
+		structscan.Scan().To("String"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'x', 'hello'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	var fe *structscan.FieldError
+
+	if _, err = schema.All(rows); !errors.As(err, &fe) || fe.Column != 0 {
+		t.Fatalf("expected field error, got %v", err)
+	}
+}
+
+type Perm int8
+
+const (
+	PermRead Perm = 1 << iota
+	PermWrite
+	PermExec
+)
+
+type Flagged struct {
+	Names []string
+	Perms []Perm
+}
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := map[int64]string{1: "read", 2: "write", 4: "exec"}
+
+	if _, err = structscan.New[Flagged](structscan.Int().Flags(map[int64]string{0: "none"}).To("Names")); err == nil {
+		t.Fatal("expected error for flag value 0")
+	}
+
+	schema, err := structscan.New[Flagged](
+		structscan.Int().Flags(names).To("Names"),
+		structscan.Int().Bits().To("Perms"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 5, 5 UNION ALL SELECT 0, 6")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []Flagged{
+		{Names: []string{"read", "exec"}, Perms: []Perm{PermRead, PermExec}},
+		{Names: []string{}, Perms: []Perm{PermWrite, PermExec}},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	rows, err = db.Query("SELECT 9, 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.All(rows); !errors.Is(err, structscan.ErrConversion) || !strings.Contains(err.Error(), "unknown flags 0x8") {
+		t.Fatalf("expected unknown flags error, got %v", err)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = structscan.New[Data](structscan.String().Canonicalize(nil).To("String")); err == nil {
+		t.Fatal("expected error for nil func")
+	}
+
+	phone := func(v string) (string, error) {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+
+			return -1
+		}, v)
+
+		if len(digits) < 6 {
+			return "", errors.New("too short")
+		}
+
+		return "+" + digits, nil
+	}
+
+	schema, err := structscan.New[Data](structscan.String().Canonicalize(phone).To("String"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '49 (30) 123-456'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.String != "+4930123456" {
+		t.Fatalf("unexpected canonical value %q", result.String)
+	}
+
+	rows, err = db.Query("SELECT '12-34'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.One(rows); !errors.Is(err, structscan.ErrConversion) || !strings.Contains(err.Error(), `canonicalize "12-34": too short`) {
+		t.Fatalf("expected canonicalize error, got %v", err)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Data](
+		structscan.String().ParseURL().Schemes("https").RequireHost().To("URL"),
+		structscan.String().ParseURL().To("URLPointer"),
+		structscan.String().ParseURL().String().To("String"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'HTTPS://example.com/a?b=c', '/relative', 'http://example.com/a b'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Data{
+		URL:        url.URL{Scheme: "https", Host: "example.com", Path: "/a", RawQuery: "b=c"},
+		URLPointer: &url.URL{Path: "/relative"},
+		String:     "http://example.com/a%20b",
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	for query, msg := range map[string]string{
+		"SELECT 'ftp://example.com', '', ''": `scheme "ftp" is not one of ["https"]`,
+		"SELECT 'https:///path', '', ''":     "missing host",
+	} {
+		rows, err = db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = schema.One(rows); !errors.Is(err, structscan.ErrConversion) || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expected %q, got %v", msg, err)
+		}
+
+		if err = rows.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+type IPRange struct {
+	First, Last netip.Addr
+}
+
+type Network struct {
+	Prefix netip.Prefix
+	Net    *net.IPNet
+	Range  IPRange
+	Legacy struct{ From, To net.IP }
+}
+
+func TestParseCIDR(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Network](
+		structscan.String().ParseCIDR().To("Prefix"),
+		structscan.String().ParseCIDR().Masked().To("Net"),
+		structscan.String().ParseIPRange().To("Range"),
+		structscan.String().ParseIPRange().To("Legacy"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT '10.1.2.3/8', '10.1.2.3/8', '10.0.0.1-10.0.0.99', 'fe80::1 - fe80::ff'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Network{
+		Prefix: netip.MustParsePrefix("10.1.2.3/8"),
+		Net:    &net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+		Range:  IPRange{First: netip.MustParseAddr("10.0.0.1"), Last: netip.MustParseAddr("10.0.0.99")},
+	}
+	expect.Legacy.From, expect.Legacy.To = net.ParseIP("fe80::1"), net.ParseIP("fe80::ff")
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if _, err = structscan.New[Network](structscan.String().ParseIPRange().To("Prefix")); !errors.Is(err, structscan.ErrNotAssignable) {
+		t.Fatalf("expected ErrNotAssignable, got %v", err)
+	}
+
+	rows, err = db.Query("SELECT '10.0.0.0/8', '10.0.0.0/8', '10.0.0.9-10.0.0.1', 'fe80::1-fe80::2'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if _, err = schema.One(rows); !errors.Is(err, structscan.ErrConversion) || !strings.Contains(err.Error(), "invalid bounds") {
+		t.Fatalf("expected invalid bounds, got %v", err)
+	}
+}
+
+type Settings struct {
+	Name   string
+	Limits struct{ Read, Write int }
+	Tags   []string
+}
+
+type Merged struct {
+	Settings Settings
+	Extra    map[string]any
+}
+
+func TestJSONPathMissingNull(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := structscan.New[Data](structscan.Scan().JSON().Path("missing").To("RawJSON"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT '{}' UNION ALL SELECT '{}'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result[0].RawJSON[0] = 'N'
+
+	if string(result[1].RawJSON) != "null" {
+		t.Fatalf("missing values share their null: %s", result[1].RawJSON)
+	}
+}
+
+func TestJSONMerge(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = structscan.New[Merged](structscan.JSON().To("Settings"), structscan.JSON().Merge().To("Settings")); err == nil {
+		t.Fatal("expected duplicate destination error")
+	}
+
+	schema, err := structscan.New[Merged](
+		structscan.JSON().Merge().To("Settings"),
+		structscan.Nullable().JSON().Merge().To("Settings"),
+		structscan.JSON().Merge().To("Extra"),
+		structscan.JSON().Merge().To("Extra"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT
+		'{"Name":"base","Limits":{"Read":1,"Write":2},"Tags":["a","b"]}',
+		'{"Limits":{"Write":5},"Tags":["c"]}',
+		'{"a":{"x":1,"y":2},"b":1}',
+		'{"a":{"y":3},"c":true}'
+	UNION ALL SELECT '{"Name":"base"}', NULL, '{}', '{"a":1}'
+	UNION ALL SELECT '{}', NULL, '{"a":1}', 'null'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := Merged{
+		Settings: Settings{Name: "base", Tags: []string{"c"}},
+		Extra:    map[string]any{"a": map[string]any{"x": 1.0, "y": 3.0}, "b": 1.0, "c": true},
+	}
+	first.Settings.Limits.Read, first.Settings.Limits.Write = 1, 5
+
+	expect := []Merged{
+		first,
+		{Settings: Settings{Name: "base"}, Extra: map[string]any{"a": 1.0}},
+		{Extra: map[string]any{"a": 1.0}},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestJSONMapErrors(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Quota struct {
+		Limits map[string]map[string]int
+		Flags  map[string]bool
+	}
+
+	schema, err := structscan.New[Quota](
+		structscan.JSON().To("Limits"),
+		structscan.JSON().To("Flags"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for query, msg := range map[string]string{
+		`SELECT '{"limits":{"min":1,"max":"x"}}', '{}'`: "key 'limits.max': cannot unmarshal string into int",
+		`SELECT '{}', '{"a":true,"b":1}'`:               "key 'b': cannot unmarshal number into bool",
+	} {
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = schema.One(rows)
+
+		var te *json.UnmarshalTypeError
+
+		if !errors.As(err, &te) || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expected %q, got %v", msg, err)
+		}
+
+		if err = rows.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAllColumnar(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Point struct {
+		ID    int64
+		Label *string
+		Tags  map[string]string
+	}
+
+	schema, err := structscan.New[Point](
+		structscan.Scan().Int().To("ID"),
+		structscan.Scan().Nullable().String().To("Label"),
+		structscan.Scan().String().To("Tags.kind"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'a', 'x' UNION ALL SELECT 2, NULL, 'y'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.AllColumnar(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := "a"
+
+	expect := structscan.Columnar{
+		Len:     2,
+		Paths:   []string{"ID", "Label", "Tags.kind"},
+		Columns: []any{[]int64{1, 2}, []*string{&a, nil}, []string{"x", "y"}},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if ids, ok := result.Column("ID").([]int64); !ok || len(ids) != 2 {
+		t.Fatalf("unexpected ID column: %v", result.Column("ID"))
+	}
+
+	if result.Column("Missing") != nil {
+		t.Fatal("expected nil for unknown path")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Export struct {
+		ID      int64
+		Name    *string
+		Created time.Time
+		Tags    map[string]string
+	}
+
+	schema, err := structscan.New[Export](
+		structscan.Scan().Int().To("ID"),
+		structscan.Scan().Nullable().String().To("Name"),
+		structscan.Scan().String().ParseTime(time.DateOnly).To("Created"),
+		structscan.Scan().String().To("Tags.kind"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT 1, 'a,b', '2024-01-02', 'x' UNION ALL SELECT 2, NULL, '2024-03-04', 'say "y"'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	var buf strings.Builder
+
+	count, err := schema.WriteCSV(rows, &buf, structscan.CSVOptions{TimeLayout: time.DateOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "ID,Name,Created,Tags.kind\n" +
+		"1,\"a,b\",2024-01-02,x\n" +
+		"2,,2024-03-04,\"say \"\"y\"\"\"\n"
+
+	if count != 2 || buf.String() != expect {
+		t.Fatalf("not equal: \n expected: %q (2) \n   result: %q (%d)", expect, buf.String(), count)
+	}
+
+	rows, err = db.Query("SELECT 1, 'a', '2024-01-02', 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	buf.Reset()
+
+	if _, err = schema.WriteCSV(rows, &buf, structscan.CSVOptions{Comma: ';', NoHeader: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := "1;a;2024-01-02T00:00:00Z;x\n"; buf.String() != expect {
+		t.Fatalf("not equal: \n expected: %q \n   result: %q", expect, buf.String())
+	}
+}
+
+type csvLabel string
+
+func (l csvLabel) MarshalText() ([]byte, error) {
+	if l == "bad" {
+		return nil, errors.New("bad label")
+	}
+
+	return []byte(l), nil
+}
+
+func TestWriteCSVError(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Export struct {
+		Label csvLabel
+	}
+
+	schema, err := structscan.New[Export](structscan.Scan().Nullable().String().To("Label"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err = schema.With(structscan.SkipNullRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT NULL UNION ALL SELECT 'ok' UNION ALL SELECT 'bad'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	var buf strings.Builder
+
+	count, err := schema.WriteCSV(rows, &buf, structscan.CSVOptions{})
+	if err == nil || err.Error() != "row 3: Label: bad label" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expect := "Label\nok\n"; count != 1 || buf.String() != expect {
+		t.Fatalf("not equal: \n expected: %q (1) \n   result: %q (%d)", expect, buf.String(), count)
+	}
 }
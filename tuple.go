@@ -0,0 +1,145 @@
+package structscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Value returns a schema scanning a single column into T, through scanner if given,
+// e.g. Value[int64]() for SELECT count(*) or Value[[]string](Scan().String().Split(","))
+// for a list column. It is the same as New[T] with at most one scanner.
+func Value[T any](scanner ...Scanner) (*Schema[T], error) {
+	if len(scanner) > 1 {
+		return nil, fmt.Errorf("value: expected at most one scanner, got %d", len(scanner))
+	}
+
+	return New[T](scanner...)
+}
+
+// ColumnReader reads a single column of rows into a slice, see Column. Like Schema, it
+// is safe for concurrent use.
+type ColumnReader[T any] struct {
+	schema *Schema[T]
+	err    error
+}
+
+// Column returns a reader of the column scanned by scanner into values of T, e.g.
+//
+//	names, err := structscan.Column[string](structscan.Scan().String().TrimSpace()).All(rows)
+//
+// Errors of scanner are reported by All.
+func Column[T any](scanner Scanner) ColumnReader[T] {
+	schema, err := New[T](scanner)
+
+	return ColumnReader[T]{schema: schema, err: err}
+}
+
+// All returns the values of the column in all rows.
+func (c ColumnReader[T]) All(rows Rows) ([]T, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return c.schema.All(rows)
+}
+
+// Pair holds the values of two types scanned from one row, see New2.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// New2 returns a schema scanning each row into a Pair, the columns of aScanners into
+// First and the following ones of bScanners into Second, e.g. for a JOIN of two tables
+// whose entities are kept apart:
+//
+//	structscan.New2[User, *Order](
+//		[]structscan.Scanner{structscan.Scan().To("ID"), structscan.Scan().To("Name")},
+//		[]structscan.Scanner{structscan.Nullable().To("ID")},
+//	)
+func New2[A, B any](aScanners, bScanners []Scanner) (*Schema[Pair[A, B]], error) {
+	scanners := make([]Scanner, 0, len(aScanners)+len(bScanners))
+
+	for _, s := range aScanners {
+		scanners = append(scanners, fieldScanner("First", s))
+	}
+
+	for _, s := range bScanners {
+		scanners = append(scanners, fieldScanner("Second", s))
+	}
+
+	return New[Pair[A, B]](scanners...)
+}
+
+// Triple holds the values of three types scanned from one row, see New3.
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// New3 is New2 for three types, the columns of cScanners going into Third.
+func New3[A, B, C any](aScanners, bScanners, cScanners []Scanner) (*Schema[Triple[A, B, C]], error) {
+	scanners := make([]Scanner, 0, len(aScanners)+len(bScanners)+len(cScanners))
+
+	for _, s := range aScanners {
+		scanners = append(scanners, fieldScanner("First", s))
+	}
+
+	for _, s := range bScanners {
+		scanners = append(scanners, fieldScanner("Second", s))
+	}
+
+	for _, s := range cScanners {
+		scanners = append(scanners, fieldScanner("Third", s))
+	}
+
+	return New[Triple[A, B, C]](scanners...)
+}
+
+// NewPair returns a schema scanning two columns into a Pair, one per scanner, e.g.
+// NewPair[string, int64](Scan(), Scan()) for SELECT name, count(*) ... GROUP BY name.
+func NewPair[A, B any](a, b Scanner) (*Schema[Pair[A, B]], error) {
+	return New2[A, B]([]Scanner{a}, []Scanner{b})
+}
+
+// NewTriple returns a schema scanning three columns into a Triple, one per scanner.
+func NewTriple[A, B, C any](a, b, c Scanner) (*Schema[Triple[A, B, C]], error) {
+	return New3[A, B, C]([]Scanner{a}, []Scanner{b}, []Scanner{c})
+}
+
+// fieldScanner makes s scan into the field of the destination named field as if it
+// were the destination. A nil pointer field is only allocated for columns that aren't
+// NULL, so that the columns of a LEFT JOIN without a match leave it nil.
+func fieldScanner(field string, s Scanner) Destination {
+	var path string
+
+	if d, ok := s.(Destination); ok && d.path != "" {
+		path = field + "." + d.path
+	}
+
+	return Destination{
+		path: path,
+		scan: func(typ reflect.Type, cfg config) (any, func(dst reflect.Value) error, error) {
+			sf, ok := derefType(typ).FieldByName(field)
+			if !ok {
+				return nil, nil, fmt.Errorf("path %s: %w", field, ErrPathNotFound)
+			}
+
+			src, set, err := scanConfig(s, sf.Type, cfg)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", field, err)
+			}
+
+			return src, func(dst reflect.Value) error {
+				elem := deref(dst).FieldByIndex(sf.Index)
+
+				if elem.Kind() == reflect.Pointer && elem.IsNil() && isNull(src) {
+					return nil
+				}
+
+				return set(deref(elem))
+			}, nil
+		},
+	}
+}
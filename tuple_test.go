@@ -0,0 +1,228 @@
+package structscan_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/go-sqlt/structscan"
+)
+
+func TestNew2(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New2[Base, *Audit](
+		[]structscan.Scanner{structscan.Scan().To("ID"), structscan.Scan().String().ToUpper().To("Name")},
+		[]structscan.Scanner{structscan.Scan().To("ID"), structscan.Scan().To("CreatedBy")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 1, 'a', 2, 'b' UNION ALL SELECT 3, 'c', 4, 'd'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []structscan.Pair[Base, *Audit]{
+		{First: Base{ID: 1, Name: "A"}, Second: &Audit{ID: 2, CreatedBy: "b"}},
+		{First: Base{ID: 3, Name: "C"}, Second: &Audit{ID: 4, CreatedBy: "d"}},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	_, err = structscan.New2[Base, Audit](
+		[]structscan.Scanner{structscan.Scan().To("CreatedBy")},
+		nil,
+	)
+	if err == nil {
+		t.Fatal("expected error for unknown path")
+	}
+}
+
+func TestNew2LeftJoin(t *testing.T) {
+	t.Parallel()
+
+	schema, err := structscan.New2[Base, *Audit](
+		[]structscan.Scanner{structscan.Scan().To("ID"), structscan.Scan().To("Name")},
+		[]structscan.Scanner{structscan.Nullable().To("ID"), structscan.Nullable().To("CreatedBy")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`WITH base(id, name) AS (VALUES (1, 'a'), (2, 'b')), audit(id, created_by) AS (VALUES (1, 'x'))
+		SELECT base.id, base.name, audit.id, audit.created_by FROM base LEFT JOIN audit ON audit.id = base.id ORDER BY base.id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := schema.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []structscan.Pair[Base, *Audit]{
+		{First: Base{ID: 1, Name: "a"}, Second: &Audit{ID: 1, CreatedBy: "x"}},
+		{First: Base{ID: 2, Name: "b"}, Second: nil},
+	}
+
+	if !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+}
+
+func TestNewPairTriple(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := structscan.NewPair[string, int64](structscan.Scan(), structscan.Scan())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 'a', 1 UNION ALL SELECT 'b', 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	pairResult, err := pairs.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []structscan.Pair[string, int64]{{"a", 1}, {"b", 2}}; !reflect.DeepEqual(pairResult, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, pairResult)
+	}
+
+	triples, err := structscan.NewTriple[string, int64, []string](
+		structscan.Scan().String().ToUpper(),
+		structscan.Nullable(),
+		structscan.Scan().String().Split(","),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT 'a', NULL, 'x,y'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	tripleResult, err := triples.One(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := (structscan.Triple[string, int64, []string]{"A", 0, []string{"x", "y"}}); !reflect.DeepEqual(tripleResult, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, tripleResult)
+	}
+}
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := structscan.Value[int64]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	if n, err := count.One(rows); err != nil || n != 3 {
+		t.Fatalf("expected 3, got %d, %v", n, err)
+	}
+
+	tags, err := structscan.Value[[]string](structscan.Scan().String().Split(","))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = db.Query("SELECT 'a,b' UNION ALL SELECT 'c'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := tags.All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := [][]string{{"a", "b"}, {"c"}}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if _, err = structscan.Value[int64](structscan.Scan(), structscan.Scan()); err == nil {
+		t.Fatal("expected error for two scanners")
+	}
+}
+
+func TestColumn(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT ' a ' UNION ALL SELECT 'b '")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rows.Close()
+
+	result, err := structscan.Column[string](structscan.Scan().String().TrimSpace().ToUpper()).All(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []string{"A", "B"}; !reflect.DeepEqual(result, expect) {
+		t.Fatalf("not equal: \n expected: %v \n   result: %v", expect, result)
+	}
+
+	if _, err = structscan.Column[string](structscan.Scan().Int()).All(rows); err == nil {
+		t.Fatal("expected error for int column into string")
+	}
+}